@@ -0,0 +1,52 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type RoleRequest struct {
+	Slug        string  `json:"slug"`
+	Name        string  `json:"name"`
+	Description *string `json:"description"`
+}
+
+type RoleResponse struct {
+	ID          uuid.UUID            `json:"id"`
+	Slug        string               `json:"slug"`
+	Name        string               `json:"name"`
+	Description *string              `json:"description"`
+	Permissions []PermissionResponse `json:"permissions,omitempty"`
+	CreatedAt   time.Time            `json:"created_at"`
+	UpdatedAt   time.Time            `json:"updated_at"`
+}
+
+type PermissionRequest struct {
+	Slug        string  `json:"slug"`
+	Name        string  `json:"name"`
+	Description *string `json:"description"`
+}
+
+type PermissionResponse struct {
+	ID          uuid.UUID `json:"id"`
+	Slug        string    `json:"slug"`
+	Name        string    `json:"name"`
+	Description *string   `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type SetRolePermissionsRequest struct {
+	PermissionIDs []uuid.UUID `json:"permission_ids"`
+}
+
+type AssignRoleRequest struct {
+	RoleID uuid.UUID `json:"role_id"`
+}
+
+// UserAccessResponse lists the roles of a user and the permissions they grant.
+type UserAccessResponse struct {
+	Roles       []string `json:"roles"`
+	Permissions []string `json:"permissions"`
+}
@@ -1,6 +1,7 @@
 package dto
 
 import (
+	"encoding/json"
 	"go-api-starter/pkg/dto"
 	"time"
 
@@ -13,13 +14,34 @@ type RegisterRequest struct {
 }
 
 type RegisterResponse struct {
-	AccessToken  string `json:"access_token"`
-	RefreshToken string `json:"refresh_token"`
+	AccessToken          string `json:"access_token,omitempty"`
+	RefreshToken         string `json:"refresh_token,omitempty"`
+	VerificationRequired bool   `json:"verification_required"`
 }
 
 type LoginRequest struct {
-	Identifier string `json:"identifiers"` // phone, username, email
+	Identifier string `json:"identifier"` // phone, username, email
 	Password   string `json:"password"`
+	ClientIP   string `json:"-"`
+}
+
+// UnmarshalJSON also accepts "identifiers", the name the field had before it was renamed,
+// so existing clients keep working. "identifier" wins when both are sent.
+func (r *LoginRequest) UnmarshalJSON(data []byte) error {
+	type loginRequest LoginRequest
+	var req struct {
+		loginRequest
+		Identifiers string `json:"identifiers"`
+	}
+	if err := json.Unmarshal(data, &req); err != nil {
+		return err
+	}
+
+	*r = LoginRequest(req.loginRequest)
+	if r.Identifier == "" {
+		r.Identifier = req.Identifiers
+	}
+	return nil
 }
 
 type LoginResponse struct {
@@ -27,11 +49,24 @@ type LoginResponse struct {
 	RefreshToken string `json:"refresh_token"`
 }
 
+type LoginBlockedDetails struct {
+	RetryAfter int `json:"retry_after"` // seconds
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
 type RefreshTokenResponse struct {
 	AccessToken  string `json:"access_token"`
 	RefreshToken string `json:"refresh_token"`
 }
 
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+	AccessToken  string `json:"-"` // taken from the Authorization header
+}
+
 type ResetPasswordRequest struct {
 	Token           string `json:"token"`
 	NewPassword     string `json:"new_password"`
@@ -45,17 +80,26 @@ type ChangePasswordRequest struct {
 	OTP             string `json:"otp"`
 }
 
+type ChangePasswordResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
 type ForgotPasswordRequest struct {
 	Identifier string `json:"identifier"`
+	ClientIP   string `json:"-"`
 }
 
+// ForgotPasswordResponse looks the same whether or not the account exists
 type ForgotPasswordResponse struct {
-	UserId uuid.UUID `json:"user_id"`
+	ChallengeID string `json:"challenge_id"`
 }
 
 type VerifyOTPRequest struct {
-	UserID uuid.UUID `json:"user_id"`
-	OTP    string    `json:"otp"`
+	Identifier  string `json:"identifier"`
+	ChallengeID string `json:"challenge_id"`
+	OTP         string `json:"otp"`
+	ClientIP    string `json:"-"`
 }
 
 type VerifyOTPResponse struct {
@@ -68,6 +112,26 @@ type ResetPassword struct {
 	ConfirmedPassword string `json:"confirmed_password"`
 }
 
+type RequestEmailVerificationRequest struct {
+	Email    string `json:"email"`
+	ClientIP string `json:"-"`
+}
+
+type ConfirmEmailVerificationRequest struct {
+	Token string `json:"token"`
+}
+
+type RequestPhoneVerificationRequest struct {
+	Phone    string `json:"phone"`
+	ClientIP string `json:"-"`
+}
+
+type ConfirmPhoneVerificationRequest struct {
+	Phone    string `json:"phone"`
+	OTP      string `json:"otp"`
+	ClientIP string `json:"-"`
+}
+
 type UserRequest struct {
 	Email           string     `json:"email"`
 	Phone           string     `json:"phone"`
@@ -0,0 +1,19 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RefreshToken tracks an issued refresh token. Tokens created by rotating each other
+// share a FamilyID, so a whole login session can be revoked at once.
+type RefreshToken struct {
+	ID         uuid.UUID  `db:"id"` // jti of the refresh token
+	FamilyID   uuid.UUID  `db:"family_id"`
+	UserID     uuid.UUID  `db:"user_id"`
+	ReplacedBy *uuid.UUID `db:"replaced_by"`
+	ExpiresAt  time.Time  `db:"expires_at"`
+	RevokedAt  *time.Time `db:"revoked_at"`
+	CreatedAt  time.Time  `db:"created_at"`
+}
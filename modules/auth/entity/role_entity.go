@@ -0,0 +1,21 @@
+package entity
+
+import (
+	"go-api-starter/pkg/entity"
+)
+
+// Role groups permissions. Users get permissions only through the roles assigned to them.
+type Role struct {
+	entity.BaseEntity
+	Slug        string  `db:"slug"`
+	Name        string  `db:"name"`
+	Description *string `db:"description"`
+}
+
+// Permission is a single grant identified by its slug, e.g. "users.read".
+type Permission struct {
+	entity.BaseEntity
+	Slug        string  `db:"slug"`
+	Name        string  `db:"name"`
+	Description *string `db:"description"`
+}
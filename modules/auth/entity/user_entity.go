@@ -1 +1,19 @@
 package entity
+
+import (
+	"go-api-starter/pkg/entity"
+	"time"
+)
+
+type User struct {
+	entity.BaseEntity
+	Email           *string    `db:"email"`
+	Phone           *string    `db:"phone"`
+	Username        *string    `db:"username"`
+	Password        string     `db:"password"`
+	EmailVerifiedAt *time.Time `db:"email_verified_at"`
+	PhoneVerifiedAt *time.Time `db:"phone_verified_at"`
+	LockedUntil     *time.Time `db:"locked_until"`
+	IsActive        bool       `db:"is_active"`
+	DeletedAt       *time.Time `db:"deleted_at"`
+}
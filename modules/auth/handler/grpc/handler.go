@@ -1 +1,90 @@
 package handler
+
+import (
+	"context"
+	"errors"
+
+	"go-api-starter/modules/auth/service"
+	"go-api-starter/pkg/constants"
+	serverService "go-api-starter/pkg/server"
+	"go-api-starter/pkg/utils"
+	authv1 "go-api-starter/proto/auth/v1"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/samber/do/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// AuthGRPCHandler implements auth.v1.AuthService, see proto/auth/v1/auth.proto.
+type AuthGRPCHandler struct {
+	authv1.UnimplementedAuthServiceServer
+
+	logger       *zerolog.Logger
+	tokenService *utils.TokenService
+	rbacService  service.RBACService
+}
+
+func NewAuthGRPCHandler(i do.Injector) (*AuthGRPCHandler, error) {
+	logger := do.MustInvoke[*zerolog.Logger](i)
+	tokenService := do.MustInvoke[*utils.TokenService](i)
+	rbacService := do.MustInvoke[service.RBACService](i)
+	return &AuthGRPCHandler{
+		logger:       logger,
+		tokenService: tokenService,
+		rbacService:  rbacService,
+	}, nil
+}
+
+// ValidateToken lets other services check an access token without sharing the signing key.
+func (h *AuthGRPCHandler) ValidateToken(ctx context.Context, req *authv1.ValidateTokenRequest) (*authv1.ValidateTokenResponse, error) {
+	if req.GetToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "token is required")
+	}
+
+	claims, err := h.tokenService.VerifyToken(ctx, req.GetToken(), constants.ScopeTokenAccess)
+	if err != nil {
+		switch {
+		case errors.Is(err, utils.ErrExpiredToken):
+			return nil, status.Error(codes.Unauthenticated, "access token has expired")
+		case errors.Is(err, utils.ErrInvalidToken), errors.Is(err, utils.ErrInvalidTokenScope), errors.Is(err, utils.ErrRevokedToken):
+			return nil, status.Error(codes.Unauthenticated, "invalid access token")
+		default:
+			h.logger.Error().Ctx(ctx).Err(err).Msg("failed to validate access token")
+			return nil, status.Error(codes.Internal, "failed to validate access token")
+		}
+	}
+
+	return &authv1.ValidateTokenResponse{
+		UserId:    claims.Subject,
+		Scope:     claims.Scope,
+		TokenId:   claims.ID,
+		ExpiresAt: timestamppb.New(claims.ExpiresAt.Time),
+	}, nil
+}
+
+// GetUserAccess returns the roles and permissions of the user whose token authenticated the call.
+func (h *AuthGRPCHandler) GetUserAccess(ctx context.Context, _ *authv1.GetUserAccessRequest) (*authv1.GetUserAccessResponse, error) {
+	claims, ok := serverService.TokenClaimsFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+
+	userID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid access token")
+	}
+
+	access, err := h.rbacService.GetUserAccess(ctx, userID)
+	if err != nil {
+		return nil, serverService.GRPCError(err)
+	}
+
+	return &authv1.GetUserAccessResponse{
+		UserId:      userID.String(),
+		Roles:       access.Roles,
+		Permissions: access.Permissions,
+	}, nil
+}
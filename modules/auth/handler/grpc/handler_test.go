@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"go-api-starter/pkg/config"
+	"go-api-starter/pkg/constants"
+	"go-api-starter/pkg/utils"
+	authv1 "go-api-starter/proto/auth/v1"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newTestClient serves the handler over an in-memory connection and returns a client of the
+// generated auth.v1.AuthService stubs.
+func newTestClient(t *testing.T) (authv1.AuthServiceClient, *utils.TokenService) {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	tokenService, err := utils.NewTokenServiceWithConfig(config.JWTConfig{
+		Algorithm:      utils.TokenAlgorithmHS256,
+		Secret:         "test-secret",
+		Issuer:         "go-api-starter",
+		AccessTokenTTL: 900,
+	}, client)
+	if err != nil {
+		t.Fatalf("NewTokenServiceWithConfig: %v", err)
+	}
+
+	logger := zerolog.Nop()
+	grpcServer := grpc.NewServer()
+	authv1.RegisterAuthServiceServer(grpcServer, &AuthGRPCHandler{logger: &logger, tokenService: tokenService})
+
+	listener := bufconn.Listen(1 << 20)
+	go func() { _ = grpcServer.Serve(listener) }()
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return authv1.NewAuthServiceClient(conn), tokenService
+}
+
+func TestAuthGRPCHandlerValidateToken(t *testing.T) {
+	ctx := context.Background()
+	client, tokenService := newTestClient(t)
+
+	token, claims, err := tokenService.GenerateToken("user-id", constants.ScopeTokenAccess)
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+
+	response, err := client.ValidateToken(ctx, &authv1.ValidateTokenRequest{Token: token})
+	if err != nil {
+		t.Fatalf("ValidateToken: %v", err)
+	}
+	if response.GetUserId() != "user-id" || response.GetScope() != constants.ScopeTokenAccess || response.GetTokenId() != claims.ID {
+		t.Fatalf("response = %v, want the claims of the token", response)
+	}
+	if !response.GetExpiresAt().AsTime().Equal(claims.ExpiresAt.Time) {
+		t.Fatalf("expires_at = %v, want %v", response.GetExpiresAt().AsTime(), claims.ExpiresAt.Time)
+	}
+
+	refreshToken, _, err := tokenService.GenerateToken("user-id", constants.ScopeTokenRefresh)
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		want  codes.Code
+	}{
+		{name: "missing", token: "", want: codes.InvalidArgument},
+		{name: "malformed", token: "not-a-token", want: codes.Unauthenticated},
+		{name: "refresh token", token: refreshToken, want: codes.Unauthenticated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.ValidateToken(ctx, &authv1.ValidateTokenRequest{Token: tt.token})
+			if got := status.Code(err); got != tt.want {
+				t.Fatalf("code = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthGRPCHandlerGetUserAccessRequiresClaims(t *testing.T) {
+	client, _ := newTestClient(t)
+
+	// Without the server's auth interceptor no claims reach the handler
+	_, err := client.GetUserAccess(context.Background(), &authv1.GetUserAccessRequest{})
+	if got := status.Code(err); got != codes.Unauthenticated {
+		t.Fatalf("code = %s, want %s", got, codes.Unauthenticated)
+	}
+}
@@ -12,13 +12,17 @@ type AuthHTTPHandler struct {
 	logger      *zerolog.Logger
 	baseHandler baseHandler.BaseHandler
 	service     service.AuthService
+	rbacService service.RBACService
 }
 
 func NewAuthHTTPHandler(i do.Injector) (*AuthHTTPHandler, error) {
 	logger := do.MustInvoke[*zerolog.Logger](i)
-	service := do.MustInvoke[service.AuthService](i)
+	authService := do.MustInvoke[service.AuthService](i)
+	rbacService := do.MustInvoke[service.RBACService](i)
 	return &AuthHTTPHandler{
-		logger:  logger,
-		service: service,
+		logger:      logger,
+		baseHandler: baseHandler.NewBaseHandler(),
+		service:     authService,
+		rbacService: rbacService,
 	}, nil
 }
@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"go-api-starter/pkg/apperrors"
+	"go-api-starter/pkg/middleware"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RequireVerified rejects users without a verified email or phone when auth.verification_policy is enabled.
+// It must run after middleware.RequireAuth.
+func (h *AuthHTTPHandler) RequireVerified(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userID, ok := middleware.GetUserID(c)
+		if !ok {
+			return h.baseHandler.Unauthorized(apperrors.ErrUnauthorized, "unauthorized")
+		}
+
+		if err := h.service.EnsureVerified(c.Request().Context(), userID); err != nil {
+			return h.baseHandler.HandleError(err)
+		}
+
+		return next(c)
+	}
+}
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"go-api-starter/modules/auth/dto"
+	"go-api-starter/modules/auth/validator"
+	"go-api-starter/pkg/apperrors"
+	"go-api-starter/pkg/middleware"
+
+	"github.com/labstack/echo/v4"
+)
+
+func (h *AuthHTTPHandler) ForgotPassword(c echo.Context) error {
+	var req dto.ForgotPasswordRequest
+	if err := c.Bind(&req); err != nil {
+		return h.baseHandler.BadRequest(apperrors.ErrInvalidInput, "invalid request body")
+	}
+
+	if result := validator.ValidateForgotPasswordRequest(&req); result.HasError() {
+		return h.baseHandler.BadRequest(apperrors.ErrInvalidInput, "validation failed", result.Errors)
+	}
+
+	req.ClientIP = c.RealIP()
+
+	response, err := h.service.ForgotPassword(c.Request().Context(), &req)
+	if err != nil {
+		return h.handleRateLimitedError(c, err)
+	}
+
+	return h.baseHandler.SuccessResponse(c, response, nil, "OTP sent successfully")
+}
+
+func (h *AuthHTTPHandler) VerifyOTP(c echo.Context) error {
+	var req dto.VerifyOTPRequest
+	if err := c.Bind(&req); err != nil {
+		return h.baseHandler.BadRequest(apperrors.ErrInvalidInput, "invalid request body")
+	}
+
+	if result := validator.ValidateVerifyOTPRequest(&req); result.HasError() {
+		return h.baseHandler.BadRequest(apperrors.ErrInvalidInput, "validation failed", result.Errors)
+	}
+
+	req.ClientIP = c.RealIP()
+
+	response, err := h.service.VerifyOTP(c.Request().Context(), &req)
+	if err != nil {
+		return h.handleRateLimitedError(c, err)
+	}
+
+	return h.baseHandler.SuccessResponse(c, response, nil, "verify OTP successfully")
+}
+
+func (h *AuthHTTPHandler) ResetPassword(c echo.Context) error {
+	var req dto.ResetPasswordRequest
+	if err := c.Bind(&req); err != nil {
+		return h.baseHandler.BadRequest(apperrors.ErrInvalidInput, "invalid request body")
+	}
+
+	if result := validator.ValidateResetPasswordRequest(&req); result.HasError() {
+		return h.baseHandler.BadRequest(apperrors.ErrInvalidInput, "validation failed", result.Errors)
+	}
+
+	if err := h.service.ResetPassword(c.Request().Context(), &req); err != nil {
+		return h.baseHandler.HandleError(err)
+	}
+
+	return h.baseHandler.SuccessResponse(c, nil, nil, "reset password successfully")
+}
+
+func (h *AuthHTTPHandler) RequestChangePasswordOTP(c echo.Context) error {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return h.baseHandler.Unauthorized(apperrors.ErrUnauthorized, "unauthorized")
+	}
+
+	if err := h.service.RequestChangePasswordOTP(c.Request().Context(), userID); err != nil {
+		return h.handleRateLimitedError(c, err)
+	}
+
+	return h.baseHandler.SuccessResponse(c, nil, nil, "OTP sent successfully")
+}
+
+func (h *AuthHTTPHandler) ChangePassword(c echo.Context) error {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return h.baseHandler.Unauthorized(apperrors.ErrUnauthorized, "unauthorized")
+	}
+
+	var req dto.ChangePasswordRequest
+	if err := c.Bind(&req); err != nil {
+		return h.baseHandler.BadRequest(apperrors.ErrInvalidInput, "invalid request body")
+	}
+
+	if result := validator.ValidateChangePasswordRequest(&req); result.HasError() {
+		return h.baseHandler.BadRequest(apperrors.ErrInvalidInput, "validation failed", result.Errors)
+	}
+
+	response, err := h.service.ChangePassword(c.Request().Context(), userID, &req)
+	if err != nil {
+		return h.handleRateLimitedError(c, err)
+	}
+
+	return h.baseHandler.SuccessResponse(c, response, nil, "change password successfully")
+}
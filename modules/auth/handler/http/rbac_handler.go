@@ -0,0 +1,204 @@
+package handler
+
+import (
+	"go-api-starter/modules/auth/dto"
+	"go-api-starter/modules/auth/validator"
+	"go-api-starter/pkg/apperrors"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+func (h *AuthHTTPHandler) ListRoles(c echo.Context) error {
+	response, err := h.rbacService.ListRoles(c.Request().Context())
+	if err != nil {
+		return h.baseHandler.HandleError(err)
+	}
+
+	return h.baseHandler.SuccessResponse(c, response, nil, "list roles successfully")
+}
+
+func (h *AuthHTTPHandler) CreateRole(c echo.Context) error {
+	var req dto.RoleRequest
+	if err := c.Bind(&req); err != nil {
+		return h.baseHandler.BadRequest(apperrors.ErrInvalidInput, "invalid request body")
+	}
+
+	if result := validator.ValidateRoleRequest(&req); result.HasError() {
+		return h.baseHandler.BadRequest(apperrors.ErrInvalidInput, "validation failed", result.Errors)
+	}
+
+	response, err := h.rbacService.CreateRole(c.Request().Context(), &req)
+	if err != nil {
+		return h.baseHandler.HandleError(err)
+	}
+
+	return h.baseHandler.SuccessResponse(c, response, nil, "create role successfully")
+}
+
+func (h *AuthHTTPHandler) GetRole(c echo.Context) error {
+	roleID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return h.baseHandler.BadRequest(apperrors.ErrInvalidInput, "invalid role id")
+	}
+
+	response, err := h.rbacService.GetRole(c.Request().Context(), roleID)
+	if err != nil {
+		return h.baseHandler.HandleError(err)
+	}
+
+	return h.baseHandler.SuccessResponse(c, response, nil, "get role successfully")
+}
+
+func (h *AuthHTTPHandler) UpdateRole(c echo.Context) error {
+	roleID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return h.baseHandler.BadRequest(apperrors.ErrInvalidInput, "invalid role id")
+	}
+
+	var req dto.RoleRequest
+	if err := c.Bind(&req); err != nil {
+		return h.baseHandler.BadRequest(apperrors.ErrInvalidInput, "invalid request body")
+	}
+
+	if result := validator.ValidateRoleRequest(&req); result.HasError() {
+		return h.baseHandler.BadRequest(apperrors.ErrInvalidInput, "validation failed", result.Errors)
+	}
+
+	response, err := h.rbacService.UpdateRole(c.Request().Context(), roleID, &req)
+	if err != nil {
+		return h.baseHandler.HandleError(err)
+	}
+
+	return h.baseHandler.SuccessResponse(c, response, nil, "update role successfully")
+}
+
+func (h *AuthHTTPHandler) DeleteRole(c echo.Context) error {
+	roleID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return h.baseHandler.BadRequest(apperrors.ErrInvalidInput, "invalid role id")
+	}
+
+	if err := h.rbacService.DeleteRole(c.Request().Context(), roleID); err != nil {
+		return h.baseHandler.HandleError(err)
+	}
+
+	return h.baseHandler.SuccessResponse(c, nil, nil, "delete role successfully")
+}
+
+func (h *AuthHTTPHandler) SetRolePermissions(c echo.Context) error {
+	roleID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return h.baseHandler.BadRequest(apperrors.ErrInvalidInput, "invalid role id")
+	}
+
+	var req dto.SetRolePermissionsRequest
+	if err := c.Bind(&req); err != nil {
+		return h.baseHandler.BadRequest(apperrors.ErrInvalidInput, "invalid request body")
+	}
+
+	if result := validator.ValidateSetRolePermissionsRequest(&req); result.HasError() {
+		return h.baseHandler.BadRequest(apperrors.ErrInvalidInput, "validation failed", result.Errors)
+	}
+
+	response, err := h.rbacService.SetRolePermissions(c.Request().Context(), roleID, &req)
+	if err != nil {
+		return h.baseHandler.HandleError(err)
+	}
+
+	return h.baseHandler.SuccessResponse(c, response, nil, "set role permissions successfully")
+}
+
+func (h *AuthHTTPHandler) ListPermissions(c echo.Context) error {
+	response, err := h.rbacService.ListPermissions(c.Request().Context())
+	if err != nil {
+		return h.baseHandler.HandleError(err)
+	}
+
+	return h.baseHandler.SuccessResponse(c, response, nil, "list permissions successfully")
+}
+
+func (h *AuthHTTPHandler) CreatePermission(c echo.Context) error {
+	var req dto.PermissionRequest
+	if err := c.Bind(&req); err != nil {
+		return h.baseHandler.BadRequest(apperrors.ErrInvalidInput, "invalid request body")
+	}
+
+	if result := validator.ValidatePermissionRequest(&req); result.HasError() {
+		return h.baseHandler.BadRequest(apperrors.ErrInvalidInput, "validation failed", result.Errors)
+	}
+
+	response, err := h.rbacService.CreatePermission(c.Request().Context(), &req)
+	if err != nil {
+		return h.baseHandler.HandleError(err)
+	}
+
+	return h.baseHandler.SuccessResponse(c, response, nil, "create permission successfully")
+}
+
+func (h *AuthHTTPHandler) DeletePermission(c echo.Context) error {
+	permissionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return h.baseHandler.BadRequest(apperrors.ErrInvalidInput, "invalid permission id")
+	}
+
+	if err := h.rbacService.DeletePermission(c.Request().Context(), permissionID); err != nil {
+		return h.baseHandler.HandleError(err)
+	}
+
+	return h.baseHandler.SuccessResponse(c, nil, nil, "delete permission successfully")
+}
+
+func (h *AuthHTTPHandler) GetUserAccess(c echo.Context) error {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return h.baseHandler.BadRequest(apperrors.ErrInvalidInput, "invalid user id")
+	}
+
+	response, err := h.rbacService.GetUserAccess(c.Request().Context(), userID)
+	if err != nil {
+		return h.baseHandler.HandleError(err)
+	}
+
+	return h.baseHandler.SuccessResponse(c, response, nil, "get user roles successfully")
+}
+
+func (h *AuthHTTPHandler) AssignUserRole(c echo.Context) error {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return h.baseHandler.BadRequest(apperrors.ErrInvalidInput, "invalid user id")
+	}
+
+	var req dto.AssignRoleRequest
+	if err := c.Bind(&req); err != nil {
+		return h.baseHandler.BadRequest(apperrors.ErrInvalidInput, "invalid request body")
+	}
+
+	if result := validator.ValidateAssignRoleRequest(&req); result.HasError() {
+		return h.baseHandler.BadRequest(apperrors.ErrInvalidInput, "validation failed", result.Errors)
+	}
+
+	if err := h.rbacService.AssignUserRole(c.Request().Context(), userID, &req); err != nil {
+		return h.baseHandler.HandleError(err)
+	}
+
+	return h.baseHandler.SuccessResponse(c, nil, nil, "assign role successfully")
+}
+
+func (h *AuthHTTPHandler) RemoveUserRole(c echo.Context) error {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return h.baseHandler.BadRequest(apperrors.ErrInvalidInput, "invalid user id")
+	}
+
+	roleID, err := uuid.Parse(c.Param("role_id"))
+	if err != nil {
+		return h.baseHandler.BadRequest(apperrors.ErrInvalidInput, "invalid role id")
+	}
+
+	if err := h.rbacService.RemoveUserRole(c.Request().Context(), userID, roleID); err != nil {
+		return h.baseHandler.HandleError(err)
+	}
+
+	return h.baseHandler.SuccessResponse(c, nil, nil, "remove role successfully")
+}
@@ -1 +1,144 @@
 package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"go-api-starter/modules/auth/dto"
+	"go-api-starter/modules/auth/validator"
+	"go-api-starter/pkg/apperrors"
+	"go-api-starter/pkg/middleware"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+func (h *AuthHTTPHandler) Register(c echo.Context) error {
+	var req dto.RegisterRequest
+	if err := c.Bind(&req); err != nil {
+		return h.baseHandler.BadRequest(apperrors.ErrInvalidInput, "invalid request body")
+	}
+
+	if result := validator.ValidateRegisterRequest(&req); result.HasError() {
+		return h.baseHandler.BadRequest(apperrors.ErrInvalidInput, "validation failed", result.Errors)
+	}
+
+	response, err := h.service.Register(c.Request().Context(), &req)
+	if err != nil {
+		return h.baseHandler.HandleError(err)
+	}
+
+	return h.baseHandler.SuccessResponse(c, response, nil, "register successfully")
+}
+
+func (h *AuthHTTPHandler) Login(c echo.Context) error {
+	var req dto.LoginRequest
+	if err := c.Bind(&req); err != nil {
+		return h.baseHandler.BadRequest(apperrors.ErrInvalidInput, "invalid request body")
+	}
+
+	if result := validator.ValidateLoginRequest(&req); result.HasError() {
+		return h.baseHandler.BadRequest(apperrors.ErrInvalidInput, "validation failed", result.Errors)
+	}
+
+	req.ClientIP = c.RealIP()
+
+	response, err := h.service.Login(c.Request().Context(), &req)
+	if err != nil {
+		return h.handleRateLimitedError(c, err)
+	}
+
+	return h.baseHandler.SuccessResponse(c, response, nil, "login successfully")
+}
+
+// handleRateLimitedError is HandleError for endpoints with attempt limits; it also sets the
+// Retry-After header when the error tells when to try again.
+func (h *AuthHTTPHandler) handleRateLimitedError(c echo.Context, err error) error {
+	var appErr *apperrors.AppError
+	if errors.As(err, &appErr) {
+		if details, ok := appErr.Details.(dto.LoginBlockedDetails); ok {
+			c.Response().Header().Set("Retry-After", strconv.Itoa(details.RetryAfter))
+		}
+	}
+	return h.baseHandler.HandleError(err)
+}
+
+func (h *AuthHTTPHandler) RefreshToken(c echo.Context) error {
+	var req dto.RefreshTokenRequest
+	if err := c.Bind(&req); err != nil {
+		return h.baseHandler.BadRequest(apperrors.ErrInvalidInput, "invalid request body")
+	}
+
+	if result := validator.ValidateRefreshTokenRequest(&req); result.HasError() {
+		return h.baseHandler.BadRequest(apperrors.ErrInvalidInput, "validation failed", result.Errors)
+	}
+
+	response, err := h.service.RefreshToken(c.Request().Context(), &req)
+	if err != nil {
+		return h.baseHandler.HandleError(err)
+	}
+
+	return h.baseHandler.SuccessResponse(c, response, nil, "refresh token successfully")
+}
+
+func (h *AuthHTTPHandler) Logout(c echo.Context) error {
+	var req dto.LogoutRequest
+	if err := c.Bind(&req); err != nil {
+		return h.baseHandler.BadRequest(apperrors.ErrInvalidInput, "invalid request body")
+	}
+
+	if result := validator.ValidateLogoutRequest(&req); result.HasError() {
+		return h.baseHandler.BadRequest(apperrors.ErrInvalidInput, "validation failed", result.Errors)
+	}
+
+	req.AccessToken, _ = middleware.BearerToken(c)
+
+	if err := h.service.Logout(c.Request().Context(), &req); err != nil {
+		return h.baseHandler.HandleError(err)
+	}
+
+	return h.baseHandler.SuccessResponse(c, nil, nil, "logout successfully")
+}
+
+func (h *AuthHTTPHandler) LogoutAll(c echo.Context) error {
+	var req dto.LogoutRequest
+	if err := c.Bind(&req); err != nil {
+		return h.baseHandler.BadRequest(apperrors.ErrInvalidInput, "invalid request body")
+	}
+
+	if result := validator.ValidateLogoutRequest(&req); result.HasError() {
+		return h.baseHandler.BadRequest(apperrors.ErrInvalidInput, "validation failed", result.Errors)
+	}
+
+	if err := h.service.LogoutAll(c.Request().Context(), &req); err != nil {
+		return h.baseHandler.HandleError(err)
+	}
+
+	return h.baseHandler.SuccessResponse(c, nil, nil, "logout from all devices successfully")
+}
+
+func (h *AuthHTTPHandler) RevokeUserSessions(c echo.Context) error {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return h.baseHandler.BadRequest(apperrors.ErrInvalidInput, "invalid user id")
+	}
+
+	if err := h.service.RevokeAllSessions(c.Request().Context(), userID); err != nil {
+		return h.baseHandler.HandleError(err)
+	}
+
+	return h.baseHandler.SuccessResponse(c, nil, nil, "revoke user sessions successfully")
+}
+
+func (h *AuthHTTPHandler) UnlockUser(c echo.Context) error {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return h.baseHandler.BadRequest(apperrors.ErrInvalidInput, "invalid user id")
+	}
+
+	if err := h.service.UnlockUser(c.Request().Context(), userID); err != nil {
+		return h.baseHandler.HandleError(err)
+	}
+
+	return h.baseHandler.SuccessResponse(c, nil, nil, "unlock user successfully")
+}
@@ -0,0 +1,250 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-api-starter/modules/auth/dto"
+	"go-api-starter/modules/auth/service"
+	"go-api-starter/pkg/apperrors"
+	baseHandler "go-api-starter/pkg/handler"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+)
+
+// fakeAuthService records the requests it receives and answers with err, or a fixed token
+// pair. Methods the tests do not use are left to the embedded interface.
+type fakeAuthService struct {
+	service.AuthService
+
+	err      error
+	register *dto.RegisterRequest
+	login    *dto.LoginRequest
+	refresh  *dto.RefreshTokenRequest
+	logout   *dto.LogoutRequest
+}
+
+func (s *fakeAuthService) Register(_ context.Context, req *dto.RegisterRequest) (*dto.RegisterResponse, error) {
+	s.register = req
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &dto.RegisterResponse{AccessToken: "access", RefreshToken: "refresh"}, nil
+}
+
+func (s *fakeAuthService) Login(_ context.Context, req *dto.LoginRequest) (*dto.LoginResponse, error) {
+	s.login = req
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &dto.LoginResponse{AccessToken: "access", RefreshToken: "refresh"}, nil
+}
+
+func (s *fakeAuthService) RefreshToken(_ context.Context, req *dto.RefreshTokenRequest) (*dto.RefreshTokenResponse, error) {
+	s.refresh = req
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &dto.RefreshTokenResponse{AccessToken: "access", RefreshToken: "refresh"}, nil
+}
+
+func (s *fakeAuthService) Logout(_ context.Context, req *dto.LogoutRequest) error {
+	s.logout = req
+	return s.err
+}
+
+func newTestHandler(authService service.AuthService) *AuthHTTPHandler {
+	logger := zerolog.Nop()
+	return &AuthHTTPHandler{
+		logger:      &logger,
+		baseHandler: baseHandler.NewBaseHandler(),
+		service:     authService,
+	}
+}
+
+// serve runs handle for a JSON POST with body and returns the recorder and the error the
+// handler returned, which echo would turn into the response.
+func serve(t *testing.T, handle echo.HandlerFunc, body string, headers map[string]string) (*httptest.ResponseRecorder, error) {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.RemoteAddr = "192.0.2.1:1234"
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	rec := httptest.NewRecorder()
+	return rec, handle(echo.New().NewContext(req, rec))
+}
+
+func assertHTTPError(t *testing.T, err error, status int, code apperrors.ErrorCode) {
+	t.Helper()
+
+	var httpErr *echo.HTTPError
+	if !errors.As(err, &httpErr) {
+		t.Fatalf("error = %v, want an HTTP error", err)
+	}
+	if httpErr.Code != status {
+		t.Fatalf("status = %d, want %d", httpErr.Code, status)
+	}
+	response, ok := httpErr.Message.(*baseHandler.ErrorResponse)
+	if !ok || response.Code != code {
+		t.Fatalf("response = %#v, want error code %d", httpErr.Message, code)
+	}
+}
+
+func assertTokenPair(t *testing.T, rec *httptest.ResponseRecorder) {
+	t.Helper()
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	var response struct {
+		Data dto.LoginResponse `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if response.Data.AccessToken != "access" || response.Data.RefreshToken != "refresh" {
+		t.Fatalf("data = %+v, want the token pair of the service", response.Data)
+	}
+}
+
+func TestAuthHTTPHandlerRegister(t *testing.T) {
+	authService := &fakeAuthService{}
+	h := newTestHandler(authService)
+
+	rec, err := serve(t, h.Register, `{"identifier":" alice@example.com ","password":"Str0ng!Password"}`, nil)
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	assertTokenPair(t, rec)
+	if authService.register.Identifier != "alice@example.com" {
+		t.Fatalf("identifier = %q, want it trimmed", authService.register.Identifier)
+	}
+
+	authService.register = nil
+	_, err = serve(t, h.Register, `{"identifier":"alice@example.com","password":"weak"}`, nil)
+	assertHTTPError(t, err, http.StatusBadRequest, apperrors.ErrInvalidInput)
+	if authService.register != nil {
+		t.Fatal("an invalid request reached the service")
+	}
+
+	authService.err = apperrors.NewAppError(apperrors.ErrAlreadyExists, "user already exists", nil)
+	_, err = serve(t, h.Register, `{"identifier":"alice@example.com","password":"Str0ng!Password"}`, nil)
+	assertHTTPError(t, err, http.StatusConflict, apperrors.ErrAlreadyExists)
+}
+
+func TestAuthHTTPHandlerLogin(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "identifier", body: `{"identifier":"alice@example.com","password":"secret"}`},
+		{name: "legacy identifiers", body: `{"identifiers":"alice@example.com","password":"secret"}`},
+		{name: "identifier wins", body: `{"identifier":"alice@example.com","identifiers":"bob@example.com","password":"secret"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authService := &fakeAuthService{}
+			h := newTestHandler(authService)
+
+			rec, err := serve(t, h.Login, tt.body, nil)
+			if err != nil {
+				t.Fatalf("Login: %v", err)
+			}
+			assertTokenPair(t, rec)
+			if authService.login.Identifier != "alice@example.com" || authService.login.Password != "secret" {
+				t.Fatalf("request = %+v, want alice@example.com with its password", authService.login)
+			}
+			if authService.login.ClientIP != "192.0.2.1" {
+				t.Fatalf("client IP = %q, want %q", authService.login.ClientIP, "192.0.2.1")
+			}
+		})
+	}
+}
+
+func TestAuthHTTPHandlerLoginErrors(t *testing.T) {
+	authService := &fakeAuthService{}
+	h := newTestHandler(authService)
+
+	_, err := serve(t, h.Login, `{"identifier":"alice@example.com"}`, nil)
+	assertHTTPError(t, err, http.StatusBadRequest, apperrors.ErrInvalidInput)
+
+	_, err = serve(t, h.Login, `{"identifier":`, nil)
+	assertHTTPError(t, err, http.StatusBadRequest, apperrors.ErrInvalidInput)
+
+	authService.err = apperrors.NewAppError(apperrors.ErrInvalidCredentials, "invalid identifier or password", nil)
+	rec, err := serve(t, h.Login, `{"identifier":"alice@example.com","password":"wrong"}`, nil)
+	assertHTTPError(t, err, http.StatusUnauthorized, apperrors.ErrInvalidCredentials)
+	if got := rec.Header().Get("Retry-After"); got != "" {
+		t.Fatalf("Retry-After = %q, want none", got)
+	}
+
+	authService.err = apperrors.NewAppError(apperrors.ErrAccountLocked, "account is temporarily locked, please try again later", nil).
+		WithDetails(dto.LoginBlockedDetails{RetryAfter: 900})
+	rec, err = serve(t, h.Login, `{"identifier":"alice@example.com","password":"secret"}`, nil)
+	assertHTTPError(t, err, http.StatusTooManyRequests, apperrors.ErrAccountLocked)
+	if got := rec.Header().Get("Retry-After"); got != "900" {
+		t.Fatalf("Retry-After = %q, want %q", got, "900")
+	}
+}
+
+func TestAuthHTTPHandlerRefreshToken(t *testing.T) {
+	authService := &fakeAuthService{}
+	h := newTestHandler(authService)
+
+	rec, err := serve(t, h.RefreshToken, `{"refresh_token":"refresh"}`, nil)
+	if err != nil {
+		t.Fatalf("RefreshToken: %v", err)
+	}
+	assertTokenPair(t, rec)
+	if authService.refresh.RefreshToken != "refresh" {
+		t.Fatalf("refresh token = %q, want %q", authService.refresh.RefreshToken, "refresh")
+	}
+
+	_, err = serve(t, h.RefreshToken, `{}`, nil)
+	assertHTTPError(t, err, http.StatusBadRequest, apperrors.ErrInvalidInput)
+
+	authService.err = apperrors.NewAppError(apperrors.ErrRefreshTokenReused, "refresh token has already been used, please login again", nil)
+	_, err = serve(t, h.RefreshToken, `{"refresh_token":"refresh"}`, nil)
+	assertHTTPError(t, err, http.StatusUnauthorized, apperrors.ErrRefreshTokenReused)
+}
+
+func TestAuthHTTPHandlerLogout(t *testing.T) {
+	authService := &fakeAuthService{}
+	h := newTestHandler(authService)
+
+	rec, err := serve(t, h.Logout, `{"refresh_token":"refresh"}`, map[string]string{echo.HeaderAuthorization: "Bearer access"})
+	if err != nil {
+		t.Fatalf("Logout: %v", err)
+	}
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if authService.logout.RefreshToken != "refresh" || authService.logout.AccessToken != "access" {
+		t.Fatalf("request = %+v, want both tokens", authService.logout)
+	}
+
+	// The access token is optional
+	if _, err := serve(t, h.Logout, `{"refresh_token":"refresh"}`, nil); err != nil {
+		t.Fatalf("Logout: %v", err)
+	}
+	if authService.logout.AccessToken != "" {
+		t.Fatalf("access token = %q, want none", authService.logout.AccessToken)
+	}
+
+	_, err = serve(t, h.Logout, `{}`, nil)
+	assertHTTPError(t, err, http.StatusBadRequest, apperrors.ErrInvalidInput)
+
+	authService.err = apperrors.NewAppError(apperrors.ErrInvalidToken, "invalid refresh token", nil)
+	_, err = serve(t, h.Logout, `{"refresh_token":"refresh"}`, nil)
+	assertHTTPError(t, err, http.StatusUnauthorized, apperrors.ErrInvalidToken)
+}
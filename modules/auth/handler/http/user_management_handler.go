@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"go-api-starter/modules/auth/dto"
+	"go-api-starter/modules/auth/validator"
+	"go-api-starter/pkg/apperrors"
+	"go-api-starter/pkg/middleware"
+	"go-api-starter/pkg/utils"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+func (h *AuthHTTPHandler) GetMe(c echo.Context) error {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return h.baseHandler.Unauthorized(apperrors.ErrUnauthorized, "unauthorized")
+	}
+
+	response, err := h.service.GetUser(c.Request().Context(), userID)
+	if err != nil {
+		return h.baseHandler.HandleError(err)
+	}
+
+	return h.baseHandler.SuccessResponse(c, response, nil, "get current user successfully")
+}
+
+func (h *AuthHTTPHandler) GetUser(c echo.Context) error {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return h.baseHandler.BadRequest(apperrors.ErrInvalidInput, "invalid user id")
+	}
+
+	response, err := h.service.GetUser(c.Request().Context(), userID)
+	if err != nil {
+		return h.baseHandler.HandleError(err)
+	}
+
+	return h.baseHandler.SuccessResponse(c, response, nil, "get user successfully")
+}
+
+func (h *AuthHTTPHandler) ListUsers(c echo.Context) error {
+	params := utils.NewQueryParams(c)
+	if isActive := c.QueryParam("is_active"); isActive != "" {
+		params.Filters["is_active"] = isActive
+	}
+
+	response, err := h.service.ListUsers(c.Request().Context(), params)
+	if err != nil {
+		return h.baseHandler.HandleError(err)
+	}
+
+	return h.baseHandler.SuccessResponse(c, response, nil, "list users successfully")
+}
+
+func (h *AuthHTTPHandler) UpdateUser(c echo.Context) error {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return h.baseHandler.BadRequest(apperrors.ErrInvalidInput, "invalid user id")
+	}
+
+	var req dto.UserRequest
+	if err := c.Bind(&req); err != nil {
+		return h.baseHandler.BadRequest(apperrors.ErrInvalidInput, "invalid request body")
+	}
+
+	if result := validator.ValidateUserRequest(&req); result.HasError() {
+		return h.baseHandler.BadRequest(apperrors.ErrInvalidInput, "validation failed", result.Errors)
+	}
+
+	response, err := h.service.UpdateUser(c.Request().Context(), userID, &req)
+	if err != nil {
+		return h.baseHandler.HandleError(err)
+	}
+
+	return h.baseHandler.SuccessResponse(c, response, nil, "update user successfully")
+}
+
+func (h *AuthHTTPHandler) DeleteUser(c echo.Context) error {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return h.baseHandler.BadRequest(apperrors.ErrInvalidInput, "invalid user id")
+	}
+
+	if err := h.service.DeleteUser(c.Request().Context(), userID); err != nil {
+		return h.baseHandler.HandleError(err)
+	}
+
+	return h.baseHandler.SuccessResponse(c, nil, nil, "delete user successfully")
+}
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"go-api-starter/modules/auth/dto"
+	"go-api-starter/modules/auth/validator"
+	"go-api-starter/pkg/apperrors"
+
+	"github.com/labstack/echo/v4"
+)
+
+func (h *AuthHTTPHandler) RequestEmailVerification(c echo.Context) error {
+	var req dto.RequestEmailVerificationRequest
+	if err := c.Bind(&req); err != nil {
+		return h.baseHandler.BadRequest(apperrors.ErrInvalidInput, "invalid request body")
+	}
+
+	if result := validator.ValidateRequestEmailVerificationRequest(&req); result.HasError() {
+		return h.baseHandler.BadRequest(apperrors.ErrInvalidInput, "validation failed", result.Errors)
+	}
+
+	req.ClientIP = c.RealIP()
+
+	if err := h.service.RequestEmailVerification(c.Request().Context(), &req); err != nil {
+		return h.handleRateLimitedError(c, err)
+	}
+
+	return h.baseHandler.SuccessResponse(c, nil, nil, "verification email sent if the address needs verifying")
+}
+
+func (h *AuthHTTPHandler) ConfirmEmailVerification(c echo.Context) error {
+	var req dto.ConfirmEmailVerificationRequest
+	if err := c.Bind(&req); err != nil {
+		return h.baseHandler.BadRequest(apperrors.ErrInvalidInput, "invalid request body")
+	}
+
+	if result := validator.ValidateConfirmEmailVerificationRequest(&req); result.HasError() {
+		return h.baseHandler.BadRequest(apperrors.ErrInvalidInput, "validation failed", result.Errors)
+	}
+
+	if err := h.service.ConfirmEmailVerification(c.Request().Context(), &req); err != nil {
+		return h.baseHandler.HandleError(err)
+	}
+
+	return h.baseHandler.SuccessResponse(c, nil, nil, "verify email successfully")
+}
+
+func (h *AuthHTTPHandler) RequestPhoneVerification(c echo.Context) error {
+	var req dto.RequestPhoneVerificationRequest
+	if err := c.Bind(&req); err != nil {
+		return h.baseHandler.BadRequest(apperrors.ErrInvalidInput, "invalid request body")
+	}
+
+	if result := validator.ValidateRequestPhoneVerificationRequest(&req); result.HasError() {
+		return h.baseHandler.BadRequest(apperrors.ErrInvalidInput, "validation failed", result.Errors)
+	}
+
+	req.ClientIP = c.RealIP()
+
+	if err := h.service.RequestPhoneVerification(c.Request().Context(), &req); err != nil {
+		return h.handleRateLimitedError(c, err)
+	}
+
+	return h.baseHandler.SuccessResponse(c, nil, nil, "OTP sent if the phone needs verifying")
+}
+
+func (h *AuthHTTPHandler) ConfirmPhoneVerification(c echo.Context) error {
+	var req dto.ConfirmPhoneVerificationRequest
+	if err := c.Bind(&req); err != nil {
+		return h.baseHandler.BadRequest(apperrors.ErrInvalidInput, "invalid request body")
+	}
+
+	if result := validator.ValidateConfirmPhoneVerificationRequest(&req); result.HasError() {
+		return h.baseHandler.BadRequest(apperrors.ErrInvalidInput, "validation failed", result.Errors)
+	}
+
+	req.ClientIP = c.RealIP()
+
+	if err := h.service.ConfirmPhoneVerification(c.Request().Context(), &req); err != nil {
+		return h.handleRateLimitedError(c, err)
+	}
+
+	return h.baseHandler.SuccessResponse(c, nil, nil, "verify phone successfully")
+}
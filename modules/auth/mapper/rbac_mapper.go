@@ -0,0 +1,73 @@
+package mapper
+
+import (
+	"go-api-starter/modules/auth/dto"
+	"go-api-starter/modules/auth/entity"
+)
+
+func ToRoleResponse(role *entity.Role, permissions []entity.Permission) *dto.RoleResponse {
+	if role == nil {
+		return nil
+	}
+
+	return &dto.RoleResponse{
+		ID:          role.ID,
+		Slug:        role.Slug,
+		Name:        role.Name,
+		Description: role.Description,
+		Permissions: ToPermissionResponses(permissions),
+		CreatedAt:   role.CreatedAt,
+		UpdatedAt:   role.UpdatedAt,
+	}
+}
+
+func ToRoleResponses(roles []entity.Role) []dto.RoleResponse {
+	responses := make([]dto.RoleResponse, 0, len(roles))
+	for i := range roles {
+		responses = append(responses, *ToRoleResponse(&roles[i], nil))
+	}
+	return responses
+}
+
+func ToPermissionResponse(permission *entity.Permission) *dto.PermissionResponse {
+	if permission == nil {
+		return nil
+	}
+
+	return &dto.PermissionResponse{
+		ID:          permission.ID,
+		Slug:        permission.Slug,
+		Name:        permission.Name,
+		Description: permission.Description,
+		CreatedAt:   permission.CreatedAt,
+		UpdatedAt:   permission.UpdatedAt,
+	}
+}
+
+func ToPermissionResponses(permissions []entity.Permission) []dto.PermissionResponse {
+	if permissions == nil {
+		return nil
+	}
+
+	responses := make([]dto.PermissionResponse, 0, len(permissions))
+	for i := range permissions {
+		responses = append(responses, *ToPermissionResponse(&permissions[i]))
+	}
+	return responses
+}
+
+func ToRoleEntity(req *dto.RoleRequest) *entity.Role {
+	return &entity.Role{
+		Slug:        req.Slug,
+		Name:        req.Name,
+		Description: req.Description,
+	}
+}
+
+func ToPermissionEntity(req *dto.PermissionRequest) *entity.Permission {
+	return &entity.Permission{
+		Slug:        req.Slug,
+		Name:        req.Name,
+		Description: req.Description,
+	}
+}
@@ -1 +1,69 @@
 package mapper
+
+import (
+	"go-api-starter/modules/auth/dto"
+	"go-api-starter/modules/auth/entity"
+	baseEntity "go-api-starter/pkg/entity"
+)
+
+func ToUserResponse(user *entity.User) *dto.UserResponse {
+	if user == nil {
+		return nil
+	}
+
+	response := &dto.UserResponse{
+		ID:              user.ID,
+		Email:           user.Email,
+		Username:        user.Username,
+		EmailVerifiedAt: user.EmailVerifiedAt,
+		PhoneVerifiedAt: user.PhoneVerifiedAt,
+		LockedUntil:     user.LockedUntil,
+		IsActive:        user.IsActive,
+		CreatedAt:       user.CreatedAt,
+		UpdatedAt:       user.UpdatedAt,
+	}
+	if user.Phone != nil {
+		response.Phone = *user.Phone
+	}
+
+	return response
+}
+
+func ToPaginatedUserDTO(page *baseEntity.Pagination[entity.User]) *dto.PaginatedUserDTO {
+	items := make([]dto.UserResponse, 0, len(page.Items))
+	for i := range page.Items {
+		items = append(items, *ToUserResponse(&page.Items[i]))
+	}
+
+	totalPages := 0
+	if page.PageSize > 0 {
+		totalPages = (page.TotalItems + page.PageSize - 1) / page.PageSize
+	}
+
+	return &dto.PaginatedUserDTO{
+		Items:      items,
+		TotalItems: page.TotalItems,
+		TotalPages: totalPages,
+		PageNumber: page.PageNumber,
+		PageSize:   page.PageSize,
+	}
+}
+
+// ToUserEntity applies an update request onto an existing user. Empty identifiers are cleared.
+func ToUserEntity(user *entity.User, req *dto.UserRequest) *entity.User {
+	user.Email = nil
+	if req.Email != "" {
+		user.Email = &req.Email
+	}
+	user.Phone = nil
+	if req.Phone != "" {
+		user.Phone = &req.Phone
+	}
+	user.Username = nil
+	if req.Username != nil && *req.Username != "" {
+		user.Username = req.Username
+	}
+	user.IsActive = req.IsActive
+
+	return user
+}
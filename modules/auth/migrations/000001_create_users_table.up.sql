@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS users (
+    id                UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    email             VARCHAR(254),
+    phone             VARCHAR(20),
+    username          VARCHAR(50),
+    password          VARCHAR(255) NOT NULL,
+    email_verified_at TIMESTAMPTZ,
+    phone_verified_at TIMESTAMPTZ,
+    locked_until      TIMESTAMPTZ,
+    is_active         BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at        TIMESTAMPTZ,
+    CONSTRAINT users_identifier_check CHECK (email IS NOT NULL OR phone IS NOT NULL OR username IS NOT NULL)
+);
+
+-- Identifiers stay unique among live users only, so a soft-deleted account frees its email, phone and username
+CREATE UNIQUE INDEX IF NOT EXISTS users_email_unique ON users (email) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS users_phone_unique ON users (phone) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS users_username_unique ON users (username) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS users_created_at_idx ON users (created_at) WHERE deleted_at IS NULL;
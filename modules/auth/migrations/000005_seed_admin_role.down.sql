@@ -0,0 +1,3 @@
+-- The seeded role and permissions are kept on rollback: the up migration skips rows that
+-- already existed, so it cannot tell which ones it created, and the role may have been
+-- assigned to users since.
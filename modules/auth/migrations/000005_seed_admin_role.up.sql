@@ -0,0 +1,16 @@
+INSERT INTO permissions (slug, name, description) VALUES
+    ('users.read', 'Read users', 'List and view user accounts'),
+    ('users.write', 'Manage users', 'Update, delete, unlock and revoke sessions of user accounts'),
+    ('roles.manage', 'Manage roles', 'Manage roles, permissions and role assignments')
+ON CONFLICT (slug) DO NOTHING;
+
+INSERT INTO roles (slug, name, description) VALUES
+    ('admin', 'Administrator', 'Full access to the admin API')
+ON CONFLICT (slug) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+CROSS JOIN permissions p
+WHERE r.slug = 'admin' AND p.slug IN ('users.read', 'users.write', 'roles.manage')
+ON CONFLICT DO NOTHING;
@@ -1,10 +1,14 @@
 package auth
 
 import (
+	grpcHandler "go-api-starter/modules/auth/handler/grpc"
 	handler "go-api-starter/modules/auth/handler/http"
+	authMigrations "go-api-starter/modules/auth/migrations"
 	repository "go-api-starter/modules/auth/repository"
+	grpcRouter "go-api-starter/modules/auth/router/grpc"
 	router "go-api-starter/modules/auth/router/http"
 	service "go-api-starter/modules/auth/service"
+	"go-api-starter/pkg/migrations"
 
 	"github.com/samber/do/v2"
 )
@@ -12,6 +16,16 @@ import (
 var Package = do.Package(
 	do.Lazy(repository.NewAuthRepository),
 	do.Lazy(service.NewAuthService),
+	do.Lazy(service.NewRBACService),
 	do.Lazy(handler.NewAuthHTTPHandler),
 	do.Lazy(router.NewAuthRouter),
+	do.Lazy(grpcHandler.NewAuthGRPCHandler),
+	do.Lazy(grpcRouter.NewAuthGRPCRouter),
 )
+
+// Migrations are the versioned SQL files of the auth module.
+var Migrations = migrations.Source{
+	Module: "auth",
+	Dir:    "modules/auth/migrations",
+	FS:     authMigrations.FS,
+}
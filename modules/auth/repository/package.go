@@ -1,24 +1,76 @@
 package repository
 
 import (
+	"context"
+	"go-api-starter/modules/auth/entity"
 	"go-api-starter/pkg/database"
+	baseEntity "go-api-starter/pkg/entity"
+	"go-api-starter/pkg/utils"
+	"time"
 
-	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/google/uuid"
 	"github.com/rs/zerolog"
 	"github.com/samber/do/v2"
 )
 
 type AuthRepository interface {
+	CreateUser(ctx context.Context, user *entity.User) (*entity.User, error)
+	GetUserByID(ctx context.Context, id uuid.UUID) (*entity.User, error)
+	GetUserByIdentifier(ctx context.Context, identifier string, identifierType utils.IdentifierType) (*entity.User, error)
+	UpdateUser(ctx context.Context, user *entity.User) (*entity.User, error)
+	DeleteUser(ctx context.Context, id uuid.UUID) (bool, error)
+	ListUsers(ctx context.Context, params *utils.QueryParams) (*baseEntity.Pagination[entity.User], error)
+	UpdateUserLockedUntil(ctx context.Context, id uuid.UUID, lockedUntil *time.Time) error
+	UpdateUserPassword(ctx context.Context, id uuid.UUID, hashedPassword string) error
+	MarkEmailVerified(ctx context.Context, id uuid.UUID) error
+	MarkPhoneVerified(ctx context.Context, id uuid.UUID) error
+
+	CreatePasswordHistory(ctx context.Context, userID uuid.UUID, hashedPassword string) error
+	GetRecentPasswordHashes(ctx context.Context, userID uuid.UUID, limit int) ([]string, error)
+
+	CreateRefreshToken(ctx context.Context, token *entity.RefreshToken) error
+	GetRefreshTokenByID(ctx context.Context, id uuid.UUID) (*entity.RefreshToken, error)
+	RotateRefreshToken(ctx context.Context, id uuid.UUID, replacedBy uuid.UUID) (bool, error)
+	RevokeRefreshTokenFamily(ctx context.Context, familyID uuid.UUID) error
+	RevokeUserRefreshTokens(ctx context.Context, userID uuid.UUID) error
+
+	CreateRole(ctx context.Context, role *entity.Role) (*entity.Role, error)
+	GetRoleByID(ctx context.Context, id uuid.UUID) (*entity.Role, error)
+	ListRoles(ctx context.Context) ([]entity.Role, error)
+	UpdateRole(ctx context.Context, role *entity.Role) (*entity.Role, error)
+	DeleteRole(ctx context.Context, id uuid.UUID) (bool, error)
+	CreatePermission(ctx context.Context, permission *entity.Permission) (*entity.Permission, error)
+	ListPermissions(ctx context.Context) ([]entity.Permission, error)
+	GetPermissionsByIDs(ctx context.Context, ids []uuid.UUID) ([]entity.Permission, error)
+	DeletePermission(ctx context.Context, id uuid.UUID) (bool, error)
+	GetRolePermissions(ctx context.Context, roleID uuid.UUID) ([]entity.Permission, error)
+	GetPermissionSlugsByRoleSlug(ctx context.Context, roleSlug string) ([]string, error)
+	SetRolePermissions(ctx context.Context, roleID uuid.UUID, permissionIDs []uuid.UUID) error
+	GetUserRoles(ctx context.Context, userID uuid.UUID) ([]entity.Role, error)
+	AssignUserRole(ctx context.Context, userID uuid.UUID, roleID uuid.UUID) error
+	RemoveUserRole(ctx context.Context, userID uuid.UUID, roleID uuid.UUID) (bool, error)
 }
 
 type authRepository struct {
-	db     *pgxpool.Pool   `do:""`
-	logger *zerolog.Logger `do:""`
+	db        *database.Postgresql `do:""`
+	txManager *database.TxManager  `do:""`
+	logger    *zerolog.Logger      `do:""`
 }
 
 func NewAuthRepository(injector do.Injector) (AuthRepository, error) {
 	db := do.MustInvoke[*database.Postgresql](injector)
+	txManager := do.MustInvoke[*database.TxManager](injector)
 	logger := do.MustInvoke[*zerolog.Logger](injector)
 
-	return &authRepository{db: db.Pool(), logger: logger}, nil
+	return &authRepository{db: db, txManager: txManager, logger: logger}, nil
+}
+
+// querier returns the transaction of ctx when the caller runs in one, otherwise the pool.
+func (r *authRepository) querier(ctx context.Context) database.Querier {
+	return r.db.Querier(ctx)
+}
+
+// readQuerier is used by list queries, which may be served by a read replica.
+func (r *authRepository) readQuerier(ctx context.Context) database.Querier {
+	return r.db.ReadQuerier(ctx)
 }
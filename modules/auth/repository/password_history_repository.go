@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+func (r *authRepository) CreatePasswordHistory(ctx context.Context, userID uuid.UUID, hashedPassword string) error {
+	query := `INSERT INTO password_histories (user_id, password) VALUES ($1, $2)`
+
+	if _, err := r.querier(ctx).Exec(ctx, query, userID, hashedPassword); err != nil {
+		r.logger.Error().Ctx(ctx).Err(err).Str("user_id", userID.String()).Msg("failed to create password history")
+		return fmt.Errorf("failed to create password history: %w", err)
+	}
+
+	return nil
+}
+
+// GetRecentPasswordHashes returns the hashes of the user's most recent previous passwords, newest first.
+func (r *authRepository) GetRecentPasswordHashes(ctx context.Context, userID uuid.UUID, limit int) ([]string, error) {
+	query := `SELECT password FROM password_histories
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2`
+
+	rows, err := r.querier(ctx).Query(ctx, query, userID, limit)
+	if err != nil {
+		r.logger.Error().Ctx(ctx).Err(err).Str("user_id", userID.String()).Msg("failed to get password history")
+		return nil, fmt.Errorf("failed to get password history: %w", err)
+	}
+	defer rows.Close()
+
+	hashes := make([]string, 0, limit)
+	for rows.Next() {
+		var hash string
+		if err := rows.Scan(&hash); err != nil {
+			return nil, fmt.Errorf("failed to scan password history: %w", err)
+		}
+		hashes = append(hashes, hash)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate password history: %w", err)
+	}
+
+	return hashes, nil
+}
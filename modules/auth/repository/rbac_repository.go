@@ -0,0 +1,329 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-api-starter/modules/auth/entity"
+	"go-api-starter/pkg/apperrors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const roleColumns = `id, slug, name, description, created_at, updated_at`
+
+const permissionColumns = `id, slug, name, description, created_at, updated_at`
+
+// isUniqueViolation reports whether err was caused by the given unique constraint.
+func isUniqueViolation(err error, constraint string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation && pgErr.ConstraintName == constraint
+}
+
+func scanRole(row pgx.Row) (*entity.Role, error) {
+	var role entity.Role
+	err := row.Scan(
+		&role.ID,
+		&role.Slug,
+		&role.Name,
+		&role.Description,
+		&role.CreatedAt,
+		&role.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+func scanPermission(row pgx.Row) (*entity.Permission, error) {
+	var permission entity.Permission
+	err := row.Scan(
+		&permission.ID,
+		&permission.Slug,
+		&permission.Name,
+		&permission.Description,
+		&permission.CreatedAt,
+		&permission.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &permission, nil
+}
+
+func collectRoles(rows pgx.Rows) ([]entity.Role, error) {
+	defer rows.Close()
+
+	roles := make([]entity.Role, 0)
+	for rows.Next() {
+		role, err := scanRole(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan role: %w", err)
+		}
+		roles = append(roles, *role)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate roles: %w", err)
+	}
+
+	return roles, nil
+}
+
+func collectPermissions(rows pgx.Rows) ([]entity.Permission, error) {
+	defer rows.Close()
+
+	permissions := make([]entity.Permission, 0)
+	for rows.Next() {
+		permission, err := scanPermission(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan permission: %w", err)
+		}
+		permissions = append(permissions, *permission)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate permissions: %w", err)
+	}
+
+	return permissions, nil
+}
+
+func collectStrings(rows pgx.Rows) ([]string, error) {
+	defer rows.Close()
+
+	values := make([]string, 0)
+	for rows.Next() {
+		var value string
+		if err := rows.Scan(&value); err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, rows.Err()
+}
+
+func (r *authRepository) CreateRole(ctx context.Context, role *entity.Role) (*entity.Role, error) {
+	query := `INSERT INTO roles (slug, name, description)
+		VALUES ($1, $2, $3)
+		RETURNING ` + roleColumns
+
+	created, err := scanRole(r.querier(ctx).QueryRow(ctx, query, role.Slug, role.Name, role.Description))
+	if err != nil {
+		if isUniqueViolation(err, "roles_slug_unique") {
+			return nil, apperrors.NewAppError(apperrors.ErrRoleAlreadyExists, "role already exists", err)
+		}
+		r.logger.Error().Ctx(ctx).Err(err).Str("slug", role.Slug).Msg("failed to create role")
+		return nil, fmt.Errorf("failed to create role: %w", err)
+	}
+
+	return created, nil
+}
+
+func (r *authRepository) GetRoleByID(ctx context.Context, id uuid.UUID) (*entity.Role, error) {
+	query := `SELECT ` + roleColumns + ` FROM roles WHERE id = $1`
+
+	role, err := scanRole(r.querier(ctx).QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		r.logger.Error().Ctx(ctx).Err(err).Str("role_id", id.String()).Msg("failed to get role")
+		return nil, fmt.Errorf("failed to get role: %w", err)
+	}
+
+	return role, nil
+}
+
+func (r *authRepository) ListRoles(ctx context.Context) ([]entity.Role, error) {
+	query := `SELECT ` + roleColumns + ` FROM roles ORDER BY slug`
+
+	rows, err := r.readQuerier(ctx).Query(ctx, query)
+	if err != nil {
+		r.logger.Error().Ctx(ctx).Err(err).Msg("failed to list roles")
+		return nil, fmt.Errorf("failed to list roles: %w", err)
+	}
+
+	return collectRoles(rows)
+}
+
+// UpdateRole saves the slug, name and description of a role. It returns nil when the role does not exist.
+func (r *authRepository) UpdateRole(ctx context.Context, role *entity.Role) (*entity.Role, error) {
+	query := `UPDATE roles SET slug = $2, name = $3, description = $4, updated_at = NOW()
+		WHERE id = $1
+		RETURNING ` + roleColumns
+
+	updated, err := scanRole(r.querier(ctx).QueryRow(ctx, query, role.ID, role.Slug, role.Name, role.Description))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		if isUniqueViolation(err, "roles_slug_unique") {
+			return nil, apperrors.NewAppError(apperrors.ErrRoleAlreadyExists, "role already exists", err)
+		}
+		r.logger.Error().Ctx(ctx).Err(err).Str("role_id", role.ID.String()).Msg("failed to update role")
+		return nil, fmt.Errorf("failed to update role: %w", err)
+	}
+
+	return updated, nil
+}
+
+func (r *authRepository) DeleteRole(ctx context.Context, id uuid.UUID) (bool, error) {
+	result, err := r.querier(ctx).Exec(ctx, `DELETE FROM roles WHERE id = $1`, id)
+	if err != nil {
+		r.logger.Error().Ctx(ctx).Err(err).Str("role_id", id.String()).Msg("failed to delete role")
+		return false, fmt.Errorf("failed to delete role: %w", err)
+	}
+
+	return result.RowsAffected() == 1, nil
+}
+
+func (r *authRepository) CreatePermission(ctx context.Context, permission *entity.Permission) (*entity.Permission, error) {
+	query := `INSERT INTO permissions (slug, name, description)
+		VALUES ($1, $2, $3)
+		RETURNING ` + permissionColumns
+
+	created, err := scanPermission(r.querier(ctx).QueryRow(ctx, query, permission.Slug, permission.Name, permission.Description))
+	if err != nil {
+		if isUniqueViolation(err, "permissions_slug_unique") {
+			return nil, apperrors.NewAppError(apperrors.ErrPermissionAlreadyExists, "permission already exists", err)
+		}
+		r.logger.Error().Ctx(ctx).Err(err).Str("slug", permission.Slug).Msg("failed to create permission")
+		return nil, fmt.Errorf("failed to create permission: %w", err)
+	}
+
+	return created, nil
+}
+
+func (r *authRepository) ListPermissions(ctx context.Context) ([]entity.Permission, error) {
+	query := `SELECT ` + permissionColumns + ` FROM permissions ORDER BY slug`
+
+	rows, err := r.readQuerier(ctx).Query(ctx, query)
+	if err != nil {
+		r.logger.Error().Ctx(ctx).Err(err).Msg("failed to list permissions")
+		return nil, fmt.Errorf("failed to list permissions: %w", err)
+	}
+
+	return collectPermissions(rows)
+}
+
+func (r *authRepository) GetPermissionsByIDs(ctx context.Context, ids []uuid.UUID) ([]entity.Permission, error) {
+	query := `SELECT ` + permissionColumns + ` FROM permissions WHERE id = ANY($1) ORDER BY slug`
+
+	rows, err := r.querier(ctx).Query(ctx, query, ids)
+	if err != nil {
+		r.logger.Error().Ctx(ctx).Err(err).Msg("failed to get permissions")
+		return nil, fmt.Errorf("failed to get permissions: %w", err)
+	}
+
+	return collectPermissions(rows)
+}
+
+func (r *authRepository) DeletePermission(ctx context.Context, id uuid.UUID) (bool, error) {
+	result, err := r.querier(ctx).Exec(ctx, `DELETE FROM permissions WHERE id = $1`, id)
+	if err != nil {
+		r.logger.Error().Ctx(ctx).Err(err).Str("permission_id", id.String()).Msg("failed to delete permission")
+		return false, fmt.Errorf("failed to delete permission: %w", err)
+	}
+
+	return result.RowsAffected() == 1, nil
+}
+
+func (r *authRepository) GetRolePermissions(ctx context.Context, roleID uuid.UUID) ([]entity.Permission, error) {
+	query := `SELECT p.id, p.slug, p.name, p.description, p.created_at, p.updated_at
+		FROM permissions p
+		JOIN role_permissions rp ON rp.permission_id = p.id
+		WHERE rp.role_id = $1
+		ORDER BY p.slug`
+
+	rows, err := r.querier(ctx).Query(ctx, query, roleID)
+	if err != nil {
+		r.logger.Error().Ctx(ctx).Err(err).Str("role_id", roleID.String()).Msg("failed to get role permissions")
+		return nil, fmt.Errorf("failed to get role permissions: %w", err)
+	}
+
+	return collectPermissions(rows)
+}
+
+// GetPermissionSlugsByRoleSlug returns the permission slugs granted to a role.
+func (r *authRepository) GetPermissionSlugsByRoleSlug(ctx context.Context, roleSlug string) ([]string, error) {
+	query := `SELECT p.slug
+		FROM permissions p
+		JOIN role_permissions rp ON rp.permission_id = p.id
+		JOIN roles ro ON ro.id = rp.role_id
+		WHERE ro.slug = $1
+		ORDER BY p.slug`
+
+	rows, err := r.querier(ctx).Query(ctx, query, roleSlug)
+	if err != nil {
+		r.logger.Error().Ctx(ctx).Err(err).Str("role", roleSlug).Msg("failed to get role permission slugs")
+		return nil, fmt.Errorf("failed to get role permission slugs: %w", err)
+	}
+
+	slugs, err := collectStrings(rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan role permission slugs: %w", err)
+	}
+	return slugs, nil
+}
+
+// SetRolePermissions replaces the permissions of a role in a single transaction.
+func (r *authRepository) SetRolePermissions(ctx context.Context, roleID uuid.UUID, permissionIDs []uuid.UUID) error {
+	return r.txManager.WithTx(ctx, func(ctx context.Context) error {
+		if _, err := r.querier(ctx).Exec(ctx, `DELETE FROM role_permissions WHERE role_id = $1`, roleID); err != nil {
+			r.logger.Error().Ctx(ctx).Err(err).Str("role_id", roleID.String()).Msg("failed to clear role permissions")
+			return fmt.Errorf("failed to clear role permissions: %w", err)
+		}
+
+		if len(permissionIDs) > 0 {
+			query := `INSERT INTO role_permissions (role_id, permission_id)
+				SELECT $1, UNNEST($2::uuid[])
+				ON CONFLICT DO NOTHING`
+			if _, err := r.querier(ctx).Exec(ctx, query, roleID, permissionIDs); err != nil {
+				r.logger.Error().Ctx(ctx).Err(err).Str("role_id", roleID.String()).Msg("failed to set role permissions")
+				return fmt.Errorf("failed to set role permissions: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+func (r *authRepository) GetUserRoles(ctx context.Context, userID uuid.UUID) ([]entity.Role, error) {
+	query := `SELECT ro.id, ro.slug, ro.name, ro.description, ro.created_at, ro.updated_at
+		FROM roles ro
+		JOIN user_roles ur ON ur.role_id = ro.id
+		WHERE ur.user_id = $1
+		ORDER BY ro.slug`
+
+	rows, err := r.querier(ctx).Query(ctx, query, userID)
+	if err != nil {
+		r.logger.Error().Ctx(ctx).Err(err).Str("user_id", userID.String()).Msg("failed to get user roles")
+		return nil, fmt.Errorf("failed to get user roles: %w", err)
+	}
+
+	return collectRoles(rows)
+}
+
+func (r *authRepository) AssignUserRole(ctx context.Context, userID uuid.UUID, roleID uuid.UUID) error {
+	query := `INSERT INTO user_roles (user_id, role_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`
+
+	if _, err := r.querier(ctx).Exec(ctx, query, userID, roleID); err != nil {
+		r.logger.Error().Ctx(ctx).Err(err).Str("user_id", userID.String()).Str("role_id", roleID.String()).Msg("failed to assign role")
+		return fmt.Errorf("failed to assign role: %w", err)
+	}
+
+	return nil
+}
+
+func (r *authRepository) RemoveUserRole(ctx context.Context, userID uuid.UUID, roleID uuid.UUID) (bool, error) {
+	result, err := r.querier(ctx).Exec(ctx, `DELETE FROM user_roles WHERE user_id = $1 AND role_id = $2`, userID, roleID)
+	if err != nil {
+		r.logger.Error().Ctx(ctx).Err(err).Str("user_id", userID.String()).Str("role_id", roleID.String()).Msg("failed to remove role")
+		return false, fmt.Errorf("failed to remove role: %w", err)
+	}
+
+	return result.RowsAffected() == 1, nil
+}
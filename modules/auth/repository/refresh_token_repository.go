@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-api-starter/modules/auth/entity"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+func (r *authRepository) CreateRefreshToken(ctx context.Context, token *entity.RefreshToken) error {
+	query := `INSERT INTO refresh_tokens (id, family_id, user_id, expires_at)
+		VALUES ($1, $2, $3, $4)`
+
+	_, err := r.querier(ctx).Exec(ctx, query, token.ID, token.FamilyID, token.UserID, token.ExpiresAt)
+	if err != nil {
+		r.logger.Error().Ctx(ctx).Err(err).Str("user_id", token.UserID.String()).Msg("failed to create refresh token")
+		return fmt.Errorf("failed to create refresh token: %w", err)
+	}
+
+	return nil
+}
+
+func (r *authRepository) GetRefreshTokenByID(ctx context.Context, id uuid.UUID) (*entity.RefreshToken, error) {
+	query := `SELECT id, family_id, user_id, replaced_by, expires_at, revoked_at, created_at
+		FROM refresh_tokens WHERE id = $1`
+
+	var token entity.RefreshToken
+	err := r.querier(ctx).QueryRow(ctx, query, id).Scan(
+		&token.ID,
+		&token.FamilyID,
+		&token.UserID,
+		&token.ReplacedBy,
+		&token.ExpiresAt,
+		&token.RevokedAt,
+		&token.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		r.logger.Error().Ctx(ctx).Err(err).Str("token_id", id.String()).Msg("failed to get refresh token")
+		return nil, fmt.Errorf("failed to get refresh token: %w", err)
+	}
+
+	return &token, nil
+}
+
+// RotateRefreshToken marks a refresh token as replaced by a new one.
+// It returns false when the token was already used or revoked, which callers must treat as reuse.
+func (r *authRepository) RotateRefreshToken(ctx context.Context, id uuid.UUID, replacedBy uuid.UUID) (bool, error) {
+	query := `UPDATE refresh_tokens SET revoked_at = NOW(), replaced_by = $2
+		WHERE id = $1 AND revoked_at IS NULL`
+
+	result, err := r.querier(ctx).Exec(ctx, query, id, replacedBy)
+	if err != nil {
+		r.logger.Error().Ctx(ctx).Err(err).Str("token_id", id.String()).Msg("failed to rotate refresh token")
+		return false, fmt.Errorf("failed to rotate refresh token: %w", err)
+	}
+
+	return result.RowsAffected() == 1, nil
+}
+
+func (r *authRepository) RevokeRefreshTokenFamily(ctx context.Context, familyID uuid.UUID) error {
+	query := `UPDATE refresh_tokens SET revoked_at = NOW()
+		WHERE family_id = $1 AND revoked_at IS NULL`
+
+	if _, err := r.querier(ctx).Exec(ctx, query, familyID); err != nil {
+		r.logger.Error().Ctx(ctx).Err(err).Str("family_id", familyID.String()).Msg("failed to revoke refresh token family")
+		return fmt.Errorf("failed to revoke refresh token family: %w", err)
+	}
+
+	return nil
+}
+
+func (r *authRepository) RevokeUserRefreshTokens(ctx context.Context, userID uuid.UUID) error {
+	query := `UPDATE refresh_tokens SET revoked_at = NOW()
+		WHERE user_id = $1 AND revoked_at IS NULL`
+
+	if _, err := r.querier(ctx).Exec(ctx, query, userID); err != nil {
+		r.logger.Error().Ctx(ctx).Err(err).Str("user_id", userID.String()).Msg("failed to revoke user refresh tokens")
+		return fmt.Errorf("failed to revoke user refresh tokens: %w", err)
+	}
+
+	return nil
+}
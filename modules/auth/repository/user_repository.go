@@ -1 +1,299 @@
 package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-api-starter/modules/auth/entity"
+	"go-api-starter/pkg/apperrors"
+	baseEntity "go-api-starter/pkg/entity"
+	"go-api-starter/pkg/utils"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const userColumns = `id, email, phone, username, password, email_verified_at, phone_verified_at,
+	locked_until, is_active, created_at, updated_at, deleted_at`
+
+// userSortColumns whitelists the columns a user list can be ordered by.
+var userSortColumns = map[string]string{
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+	"email":      "email",
+	"phone":      "phone",
+	"username":   "username",
+}
+
+// userUniqueConstraintErrors maps unique indexes on users to their application error.
+var userUniqueConstraintErrors = map[string]*apperrors.AppError{
+	"users_email_unique":    apperrors.NewAppError(apperrors.ErrEmailAlreadyExists, "email already exists", nil),
+	"users_phone_unique":    apperrors.NewAppError(apperrors.ErrPhoneAlreadyExists, "phone already exists", nil),
+	"users_username_unique": apperrors.NewAppError(apperrors.ErrUsernameAlreadyExists, "username already exists", nil),
+}
+
+// mapUserWriteError turns unique violations into application errors the caller can show to the client.
+func mapUserWriteError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
+		if appErr, ok := userUniqueConstraintErrors[pgErr.ConstraintName]; ok {
+			return apperrors.NewAppError(appErr.Code, appErr.Message, err)
+		}
+		return apperrors.NewAppError(apperrors.ErrAlreadyExists, "user already exists", err)
+	}
+	return nil
+}
+
+func scanUser(row pgx.Row) (*entity.User, error) {
+	var user entity.User
+	err := row.Scan(
+		&user.ID,
+		&user.Email,
+		&user.Phone,
+		&user.Username,
+		&user.Password,
+		&user.EmailVerifiedAt,
+		&user.PhoneVerifiedAt,
+		&user.LockedUntil,
+		&user.IsActive,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+		&user.DeletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *authRepository) CreateUser(ctx context.Context, user *entity.User) (*entity.User, error) {
+	query := `INSERT INTO users (email, phone, username, password, is_active)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING ` + userColumns
+
+	created, err := scanUser(r.querier(ctx).QueryRow(ctx, query,
+		user.Email,
+		user.Phone,
+		user.Username,
+		user.Password,
+		user.IsActive,
+	))
+	if err != nil {
+		if appErr := mapUserWriteError(err); appErr != nil {
+			return nil, appErr
+		}
+		r.logger.Error().Ctx(ctx).Err(err).Msg("failed to create user")
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	return created, nil
+}
+
+func (r *authRepository) GetUserByID(ctx context.Context, id uuid.UUID) (*entity.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE id = $1 AND deleted_at IS NULL`
+
+	user, err := scanUser(r.querier(ctx).QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		r.logger.Error().Ctx(ctx).Err(err).Str("user_id", id.String()).Msg("failed to get user by id")
+		return nil, fmt.Errorf("failed to get user by id: %w", err)
+	}
+
+	return user, nil
+}
+
+func (r *authRepository) GetUserByIdentifier(ctx context.Context, identifier string, identifierType utils.IdentifierType) (*entity.User, error) {
+	var column string
+	switch identifierType {
+	case utils.IdentifierTypeEmail:
+		column = "email"
+	case utils.IdentifierTypePhone:
+		column = "phone"
+	case utils.IdentifierTypeUsername:
+		column = "username"
+	default:
+		return nil, fmt.Errorf("unsupported identifier type: %s", identifierType)
+	}
+
+	query := `SELECT ` + userColumns + ` FROM users WHERE ` + column + ` = $1 AND deleted_at IS NULL`
+
+	user, err := scanUser(r.querier(ctx).QueryRow(ctx, query, identifier))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		r.logger.Error().Ctx(ctx).Err(err).Str("identifier_type", string(identifierType)).Msg("failed to get user by identifier")
+		return nil, fmt.Errorf("failed to get user by identifier: %w", err)
+	}
+
+	return user, nil
+}
+
+func (r *authRepository) UpdateUserLockedUntil(ctx context.Context, id uuid.UUID, lockedUntil *time.Time) error {
+	query := `UPDATE users SET locked_until = $2, updated_at = NOW() WHERE id = $1 AND deleted_at IS NULL`
+
+	if _, err := r.querier(ctx).Exec(ctx, query, id, lockedUntil); err != nil {
+		r.logger.Error().Ctx(ctx).Err(err).Str("user_id", id.String()).Msg("failed to update user locked until")
+		return fmt.Errorf("failed to update user locked until: %w", err)
+	}
+
+	return nil
+}
+
+func (r *authRepository) UpdateUserPassword(ctx context.Context, id uuid.UUID, hashedPassword string) error {
+	query := `UPDATE users SET password = $2, updated_at = NOW() WHERE id = $1 AND deleted_at IS NULL`
+
+	if _, err := r.querier(ctx).Exec(ctx, query, id, hashedPassword); err != nil {
+		r.logger.Error().Ctx(ctx).Err(err).Str("user_id", id.String()).Msg("failed to update user password")
+		return fmt.Errorf("failed to update user password: %w", err)
+	}
+
+	return nil
+}
+
+func (r *authRepository) MarkEmailVerified(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE users SET email_verified_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND email_verified_at IS NULL AND deleted_at IS NULL`
+
+	if _, err := r.querier(ctx).Exec(ctx, query, id); err != nil {
+		r.logger.Error().Ctx(ctx).Err(err).Str("user_id", id.String()).Msg("failed to mark email verified")
+		return fmt.Errorf("failed to mark email verified: %w", err)
+	}
+
+	return nil
+}
+
+func (r *authRepository) MarkPhoneVerified(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE users SET phone_verified_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND phone_verified_at IS NULL AND deleted_at IS NULL`
+
+	if _, err := r.querier(ctx).Exec(ctx, query, id); err != nil {
+		r.logger.Error().Ctx(ctx).Err(err).Str("user_id", id.String()).Msg("failed to mark phone verified")
+		return fmt.Errorf("failed to mark phone verified: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateUser saves the profile fields of a user and returns the updated record.
+// It returns nil when the user does not exist.
+func (r *authRepository) UpdateUser(ctx context.Context, user *entity.User) (*entity.User, error) {
+	query := `UPDATE users SET email = $2, phone = $3, username = $4, is_active = $5,
+			email_verified_at = $6, phone_verified_at = $7, updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
+		RETURNING ` + userColumns
+
+	updated, err := scanUser(r.querier(ctx).QueryRow(ctx, query,
+		user.ID,
+		user.Email,
+		user.Phone,
+		user.Username,
+		user.IsActive,
+		user.EmailVerifiedAt,
+		user.PhoneVerifiedAt,
+	))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		if appErr := mapUserWriteError(err); appErr != nil {
+			return nil, appErr
+		}
+		r.logger.Error().Ctx(ctx).Err(err).Str("user_id", user.ID.String()).Msg("failed to update user")
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+
+	return updated, nil
+}
+
+// DeleteUser soft-deletes a user. It returns false when the user does not exist.
+func (r *authRepository) DeleteUser(ctx context.Context, id uuid.UUID) (bool, error) {
+	query := `UPDATE users SET deleted_at = NOW(), is_active = FALSE, updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL`
+
+	result, err := r.querier(ctx).Exec(ctx, query, id)
+	if err != nil {
+		r.logger.Error().Ctx(ctx).Err(err).Str("user_id", id.String()).Msg("failed to delete user")
+		return false, fmt.Errorf("failed to delete user: %w", err)
+	}
+
+	return result.RowsAffected() == 1, nil
+}
+
+// ListUsers returns a page of users. Search matches email, phone and username.
+func (r *authRepository) ListUsers(ctx context.Context, params *utils.QueryParams) (*baseEntity.Pagination[entity.User], error) {
+	conditions := []string{"deleted_at IS NULL"}
+	args := []any{}
+
+	if search := utils.TrimSpace(params.Search); search != "" {
+		args = append(args, "%"+search+"%")
+		conditions = append(conditions, fmt.Sprintf("(email ILIKE $%d OR phone ILIKE $%d OR username ILIKE $%d)",
+			len(args), len(args), len(args)))
+	}
+	if isActive, ok := params.Filters["is_active"]; ok {
+		args = append(args, isActive == "true")
+		conditions = append(conditions, fmt.Sprintf("is_active = $%d", len(args)))
+	}
+
+	where := " WHERE " + strings.Join(conditions, " AND ")
+
+	var total int
+	if err := r.readQuerier(ctx).QueryRow(ctx, `SELECT COUNT(*) FROM users`+where, args...).Scan(&total); err != nil {
+		r.logger.Error().Ctx(ctx).Err(err).Msg("failed to count users")
+		return nil, fmt.Errorf("failed to count users: %w", err)
+	}
+
+	orderBy := "created_at DESC"
+	if params.OrderBy != "" {
+		column, direction, _ := strings.Cut(params.OrderBy, ":")
+		if sortColumn, ok := userSortColumns[column]; ok {
+			orderBy = sortColumn + " ASC"
+			if strings.EqualFold(direction, "desc") {
+				orderBy = sortColumn + " DESC"
+			}
+		}
+	}
+
+	pageNumber := min(max(params.PageNumber, 1), utils.MaxPageNumber)
+	pageSize := min(params.PageSize, utils.MaxPageSize)
+	if pageSize <= 0 {
+		pageSize = utils.DefaultPageSize
+	}
+
+	args = append(args, pageSize, (pageNumber-1)*pageSize)
+	query := `SELECT ` + userColumns + ` FROM users` + where +
+		` ORDER BY ` + orderBy +
+		fmt.Sprintf(` LIMIT $%d OFFSET $%d`, len(args)-1, len(args))
+
+	rows, err := r.readQuerier(ctx).Query(ctx, query, args...)
+	if err != nil {
+		r.logger.Error().Ctx(ctx).Err(err).Msg("failed to list users")
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+	defer rows.Close()
+
+	users := make([]entity.User, 0)
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, *user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate users: %w", err)
+	}
+
+	return &baseEntity.Pagination[entity.User]{
+		Items:      users,
+		TotalItems: total,
+		PageNumber: pageNumber,
+		PageSize:   pageSize,
+	}, nil
+}
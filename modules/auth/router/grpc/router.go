@@ -1 +1,29 @@
 package router
+
+import (
+	authHandler "go-api-starter/modules/auth/handler/grpc"
+	serverService "go-api-starter/pkg/server"
+	authv1 "go-api-starter/proto/auth/v1"
+
+	"github.com/samber/do/v2"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+type AuthGRPCRouter struct {
+	handler *authHandler.AuthGRPCHandler
+}
+
+func NewAuthGRPCRouter(i do.Injector) (*AuthGRPCRouter, error) {
+	h := do.MustInvoke[*authHandler.AuthGRPCHandler](i)
+	return &AuthGRPCRouter{
+		handler: h,
+	}, nil
+}
+
+func (r *AuthGRPCRouter) Register(server *serverService.GRPCServer) {
+	authv1.RegisterAuthServiceServer(server.Server, r.handler)
+
+	// The token being validated is the caller's credential, so no bearer token is required
+	server.RegisterPublicMethods(authv1.AuthService_ValidateToken_FullMethodName)
+	server.Health.SetServingStatus(authv1.AuthService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+}
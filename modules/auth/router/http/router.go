@@ -2,33 +2,118 @@ package router
 
 import (
 	authHandler "go-api-starter/modules/auth/handler/http"
+	authService "go-api-starter/modules/auth/service"
+	"go-api-starter/pkg/cache"
+	"go-api-starter/pkg/config"
+	"go-api-starter/pkg/constants"
+	"go-api-starter/pkg/middleware"
+	"go-api-starter/pkg/utils"
 
 	"github.com/labstack/echo/v4"
 	"github.com/samber/do/v2"
 )
 
 type AuthHTTPRouter struct {
-	handler *authHandler.AuthHTTPHandler
+	handler         *authHandler.AuthHTTPHandler
+	requireAuth     echo.MiddlewareFunc
+	requireVerified echo.MiddlewareFunc
+	requireInternal echo.MiddlewareFunc
+	rbacService     authService.RBACService
 }
 
 func NewAuthRouter(i do.Injector) (*AuthHTTPRouter, error) {
 	h := do.MustInvoke[*authHandler.AuthHTTPHandler](i)
+	appConfig := do.MustInvoke[*config.Config](i)
+	redisClient := do.MustInvoke[*cache.Redis](i)
+	tokenService := do.MustInvoke[*utils.TokenService](i)
+	rbacService := do.MustInvoke[authService.RBACService](i)
 	return &AuthHTTPRouter{
-		handler: h,
+		handler:         h,
+		requireAuth:     middleware.RequireAuth(tokenService),
+		requireVerified: h.RequireVerified,
+		requireInternal: middleware.RequireInternalAuth(appConfig.Internal, redisClient.Client()),
+		rbacService:     rbacService,
 	}, nil
 }
 
 func (r *AuthHTTPRouter) Register(e *echo.Echo) {
 	r.registerPublicRoutes(e)
+	r.registerAdminRoutes(e)
 	r.registerInternalRoutes(e)
 }
 
 func (r *AuthHTTPRouter) registerPublicRoutes(e *echo.Echo) {
-	// group := e.Group("/api/v1/auth")
-	// Add public routes here
+	group := e.Group("/api/v1/auth")
+	group.POST("/register", r.handler.Register)
+	group.POST("/login", r.handler.Login)
+	group.POST("/refresh", r.handler.RefreshToken)
+	group.POST("/logout", r.handler.Logout)
+	group.POST("/logout-all", r.handler.LogoutAll)
+	group.POST("/forgot-password", r.handler.ForgotPassword)
+	group.POST("/verify-otp", r.handler.VerifyOTP)
+	group.POST("/reset-password", r.handler.ResetPassword)
+	group.POST("/verify-email/request", r.handler.RequestEmailVerification)
+	group.POST("/verify-email/confirm", r.handler.ConfirmEmailVerification)
+	group.POST("/verify-phone/request", r.handler.RequestPhoneVerification)
+	group.POST("/verify-phone/confirm", r.handler.ConfirmPhoneVerification)
+	group.POST("/change-password/otp", r.handler.RequestChangePasswordOTP, r.requireAuth, r.requireVerified)
+	group.POST("/change-password", r.handler.ChangePassword, r.requireAuth, r.requireVerified)
+	// Unverified users can still read their own profile to see what is left to verify
+	group.GET("/me", r.handler.GetMe, r.requireAuth)
 }
 
+// registerAdminRoutes exposes user and RBAC management to authenticated, verified users holding the matching permission.
+func (r *AuthHTTPRouter) registerAdminRoutes(e *echo.Echo) {
+	group := e.Group("/api/v1/auth/admin", r.requireAuth, r.requireVerified)
+
+	canReadUsers := middleware.RequirePermission(r.rbacService, constants.PermissionUsersRead)
+	canWriteUsers := middleware.RequirePermission(r.rbacService, constants.PermissionUsersWrite)
+	canManageRoles := middleware.RequirePermission(r.rbacService, constants.PermissionRolesManage)
+
+	group.GET("/users", r.handler.ListUsers, canReadUsers)
+	group.GET("/users/:id", r.handler.GetUser, canReadUsers)
+	group.PUT("/users/:id", r.handler.UpdateUser, canWriteUsers)
+	group.DELETE("/users/:id", r.handler.DeleteUser, canWriteUsers)
+	group.POST("/users/:id/revoke-sessions", r.handler.RevokeUserSessions, canWriteUsers)
+	group.POST("/users/:id/unlock", r.handler.UnlockUser, canWriteUsers)
+	group.GET("/users/:id/roles", r.handler.GetUserAccess, canReadUsers)
+	group.POST("/users/:id/roles", r.handler.AssignUserRole, canManageRoles)
+	group.DELETE("/users/:id/roles/:role_id", r.handler.RemoveUserRole, canManageRoles)
+
+	group.GET("/roles", r.handler.ListRoles, canManageRoles)
+	group.POST("/roles", r.handler.CreateRole, canManageRoles)
+	group.GET("/roles/:id", r.handler.GetRole, canManageRoles)
+	group.PUT("/roles/:id", r.handler.UpdateRole, canManageRoles)
+	group.DELETE("/roles/:id", r.handler.DeleteRole, canManageRoles)
+	group.PUT("/roles/:id/permissions", r.handler.SetRolePermissions, canManageRoles)
+
+	group.GET("/permissions", r.handler.ListPermissions, canManageRoles)
+	group.POST("/permissions", r.handler.CreatePermission, canManageRoles)
+	group.DELETE("/permissions/:id", r.handler.DeletePermission, canManageRoles)
+}
+
+// registerInternalRoutes exposes the same management endpoints to other services.
+// Callers authenticate with signed requests; user tokens are not accepted here.
 func (r *AuthHTTPRouter) registerInternalRoutes(e *echo.Echo) {
-	// group := e.Group("/internal/api/v1/auth")
-	// Add internal routes here
+	group := e.Group("/internal/api/v1/auth", r.requireInternal)
+	group.GET("/users", r.handler.ListUsers)
+	group.GET("/users/:id", r.handler.GetUser)
+	group.PUT("/users/:id", r.handler.UpdateUser)
+	group.DELETE("/users/:id", r.handler.DeleteUser)
+	group.POST("/users/:id/revoke-sessions", r.handler.RevokeUserSessions)
+	group.POST("/users/:id/unlock", r.handler.UnlockUser)
+	group.GET("/users/:id/roles", r.handler.GetUserAccess)
+	group.POST("/users/:id/roles", r.handler.AssignUserRole)
+	group.DELETE("/users/:id/roles/:role_id", r.handler.RemoveUserRole)
+
+	group.GET("/roles", r.handler.ListRoles)
+	group.POST("/roles", r.handler.CreateRole)
+	group.GET("/roles/:id", r.handler.GetRole)
+	group.PUT("/roles/:id", r.handler.UpdateRole)
+	group.DELETE("/roles/:id", r.handler.DeleteRole)
+	group.PUT("/roles/:id/permissions", r.handler.SetRolePermissions)
+
+	group.GET("/permissions", r.handler.ListPermissions)
+	group.POST("/permissions", r.handler.CreatePermission)
+	group.DELETE("/permissions/:id", r.handler.DeletePermission)
 }
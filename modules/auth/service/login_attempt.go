@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"time"
+
+	"go-api-starter/modules/auth/dto"
+	"go-api-starter/modules/auth/entity"
+	"go-api-starter/pkg/apperrors"
+	"go-api-starter/pkg/constants"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+func loginAttemptsIdentifierKey(identifier string) string {
+	return constants.RedisKeyLoginAttemptsIdentifier + strings.ToLower(identifier)
+}
+
+func loginAttemptsIPKey(ip string) string {
+	return constants.RedisKeyLoginAttemptsIP + ip
+}
+
+func passwordAttemptsKey(userID uuid.UUID) string {
+	return constants.RedisKeyPasswordAttempts + userID.String()
+}
+
+func newRetryAfterError(code apperrors.ErrorCode, message string, retryAfter time.Duration) *apperrors.AppError {
+	return apperrors.NewAppError(code, message, nil).WithDetails(dto.LoginBlockedDetails{
+		RetryAfter: int(math.Ceil(retryAfter.Seconds())),
+	})
+}
+
+// checkLoginBlocked rejects a login attempt while the client IP or the identifier has too many recent failures.
+func (s *authService) checkLoginBlocked(ctx context.Context, identifier string, clientIP string) error {
+	limits := s.loginLimits.Load()
+
+	if clientIP != "" {
+		if blocked, retryAfter, err := s.isAttemptLimitReached(ctx, loginAttemptsIPKey(clientIP), limits.MaxLoginAttemptsPerIP); err != nil {
+			return apperrors.NewAppError(apperrors.ErrInternalServer, "failed to login", err)
+		} else if blocked {
+			return newRetryAfterError(apperrors.ErrTooManyLoginAttempts, "too many login attempts, please try again later", retryAfter)
+		}
+	}
+
+	if blocked, retryAfter, err := s.isAttemptLimitReached(ctx, loginAttemptsIdentifierKey(identifier), limits.MaxLoginAttempts); err != nil {
+		return apperrors.NewAppError(apperrors.ErrInternalServer, "failed to login", err)
+	} else if blocked {
+		return newRetryAfterError(apperrors.ErrAccountLocked, "account is temporarily locked, please try again later", retryAfter)
+	}
+
+	return nil
+}
+
+func (s *authService) isAttemptLimitReached(ctx context.Context, key string, limit int) (bool, time.Duration, error) {
+	attempts, err := s.redis.Get(ctx, key).Int()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return false, 0, nil
+		}
+		return false, 0, err
+	}
+	if attempts < limit {
+		return false, 0, nil
+	}
+
+	ttl, err := s.redis.TTL(ctx, key).Result()
+	if err != nil {
+		return false, 0, err
+	}
+	return true, ttl, nil
+}
+
+// recordFailedLogin counts a failed attempt for the identifier and client IP.
+// Once the identifier reaches auth.max_login_attempts the user, if any, is locked in Postgres for auth.block_duration.
+func (s *authService) recordFailedLogin(ctx context.Context, identifier string, clientIP string, user *entity.User) error {
+	limits := s.loginLimits.Load()
+	blockDuration := time.Duration(limits.BlockDuration) * time.Second
+
+	attempts, err := s.incrementAttempts(ctx, loginAttemptsIdentifierKey(identifier), blockDuration)
+	if err != nil {
+		return err
+	}
+
+	if clientIP != "" {
+		if _, err := s.incrementAttempts(ctx, loginAttemptsIPKey(clientIP), blockDuration); err != nil {
+			return err
+		}
+	}
+
+	if attempts < int64(limits.MaxLoginAttempts) || user == nil {
+		return nil
+	}
+
+	lockedUntil := time.Now().Add(blockDuration)
+	if err := s.authRepository.UpdateUserLockedUntil(ctx, user.ID, &lockedUntil); err != nil {
+		return err
+	}
+
+	s.logger.Warn().
+		Ctx(ctx).
+		Str("user_id", user.ID.String()).
+		Str("client_ip", clientIP).
+		Time("locked_until", lockedUntil).
+		Msg("user locked after too many failed login attempts")
+
+	return nil
+}
+
+// recordFailedPassword counts a wrong current password given by a signed-in user. Once
+// auth.max_login_attempts is reached within auth.block_duration the user is locked as after failed logins.
+func (s *authService) recordFailedPassword(ctx context.Context, user *entity.User) error {
+	limits := s.loginLimits.Load()
+	blockDuration := time.Duration(limits.BlockDuration) * time.Second
+
+	attempts, err := s.incrementAttempts(ctx, passwordAttemptsKey(user.ID), blockDuration)
+	if err != nil {
+		return err
+	}
+	if attempts < int64(limits.MaxLoginAttempts) {
+		return nil
+	}
+
+	lockedUntil := time.Now().Add(blockDuration)
+	if err := s.authRepository.UpdateUserLockedUntil(ctx, user.ID, &lockedUntil); err != nil {
+		return err
+	}
+
+	s.logger.Warn().
+		Ctx(ctx).
+		Str("user_id", user.ID.String()).
+		Time("locked_until", lockedUntil).
+		Msg("user locked after too many wrong passwords on password change")
+
+	return nil
+}
+
+// incrementAttempts increases a failure counter; the window starts with the first failure.
+func (s *authService) incrementAttempts(ctx context.Context, key string, window time.Duration) (int64, error) {
+	pipe := s.redis.TxPipeline()
+	incr := pipe.Incr(ctx, key)
+	pipe.ExpireNX(ctx, key, window)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+	return incr.Val(), nil
+}
+
+// clearLoginAttempts resets the identifier counters of a user.
+func (s *authService) clearLoginAttempts(ctx context.Context, identifiers ...*string) error {
+	keys := make([]string, 0, len(identifiers))
+	for _, identifier := range identifiers {
+		if identifier != nil && *identifier != "" {
+			keys = append(keys, loginAttemptsIdentifierKey(*identifier))
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	return s.redis.Del(ctx, keys...).Err()
+}
+
+func (s *authService) UnlockUser(ctx context.Context, userID uuid.UUID) error {
+	user, err := s.authRepository.GetUserByID(ctx, userID)
+	if err != nil {
+		return apperrors.NewAppError(apperrors.ErrInternalServer, "failed to unlock user", err)
+	}
+	if user == nil {
+		return apperrors.NewAppError(apperrors.ErrNotFound, "user not found", nil)
+	}
+
+	if err := s.authRepository.UpdateUserLockedUntil(ctx, user.ID, nil); err != nil {
+		return apperrors.NewAppError(apperrors.ErrInternalServer, "failed to unlock user", err)
+	}
+
+	if err := s.clearLoginAttempts(ctx, user.Email, user.Phone, user.Username); err != nil {
+		return apperrors.NewAppError(apperrors.ErrInternalServer, "failed to unlock user", err)
+	}
+	if err := s.redis.Del(ctx, passwordAttemptsKey(user.ID)).Err(); err != nil {
+		return apperrors.NewAppError(apperrors.ErrInternalServer, "failed to unlock user", err)
+	}
+
+	s.logger.Info().Ctx(ctx).Str("user_id", user.ID.String()).Msg("user unlocked")
+	return nil
+}
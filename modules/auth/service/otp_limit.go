@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"go-api-starter/pkg/apperrors"
+	"go-api-starter/pkg/constants"
+	"go-api-starter/pkg/utils"
+)
+
+// limitOTPRequest counts a request for a new code against the identifier and the client IP,
+// so codes cannot be requested over and over to brute-force them or to pump SMS.
+// It is called before looking the identifier up, so unknown identifiers are limited the same way.
+func (s *authService) limitOTPRequest(ctx context.Context, identifier string, clientIP string) error {
+	key := constants.RedisKeyOTPRequests + "identifier:" + strings.ToLower(identifier)
+	if err := s.otpService.Limit(ctx, key, constants.MaxOTPRequestsPerIdentifier, constants.OTPRateLimitWindow); err != nil {
+		return otpAppError(err, "failed to send OTP")
+	}
+
+	if clientIP != "" {
+		key := constants.RedisKeyOTPRequests + "ip:" + clientIP
+		if err := s.otpService.Limit(ctx, key, constants.MaxOTPRequestsPerIP, constants.OTPRateLimitWindow); err != nil {
+			return otpAppError(err, "failed to send OTP")
+		}
+	}
+
+	return nil
+}
+
+// limitOTPVerification counts a verification attempt of the client IP.
+// Wrong codes per identifier are limited by the OTP service itself.
+func (s *authService) limitOTPVerification(ctx context.Context, clientIP string) error {
+	if clientIP == "" {
+		return nil
+	}
+
+	key := constants.RedisKeyOTPVerifications + "ip:" + clientIP
+	if err := s.otpService.Limit(ctx, key, constants.MaxOTPVerificationsPerIP, constants.OTPRateLimitWindow); err != nil {
+		return otpAppError(err, "failed to verify OTP")
+	}
+	return nil
+}
+
+// otpAppError maps errors of the OTP service to application errors.
+func otpAppError(err error, message string) error {
+	var limitErr *utils.OTPLimitError
+	switch {
+	case errors.As(err, &limitErr):
+		return newRetryAfterError(apperrors.ErrTooManyOTPRequests, "too many OTP attempts, please try again later", limitErr.RetryAfter)
+	case errors.Is(err, utils.ErrOTPInvalid), errors.Is(err, utils.ErrOTPNotFound):
+		return apperrors.NewAppError(apperrors.ErrInvalidOTP, "invalid or expired OTP", err)
+	default:
+		return apperrors.NewAppError(apperrors.ErrInternalServer, message, err)
+	}
+}
+
+// deliverInBackground sends a code or link without making the caller wait, so neither the time
+// it takes nor a delivery failure tells whether the account exists. Failures are logged.
+func (s *authService) deliverInBackground(ctx context.Context, send func(ctx context.Context) error) {
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		if err := send(ctx); err != nil {
+			s.logger.Error().Ctx(ctx).Err(err).Msg("failed to deliver verification message")
+		}
+	}()
+}
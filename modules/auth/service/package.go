@@ -1,25 +1,141 @@
 package service
 
 import (
+	"context"
+	"go-api-starter/modules/auth/dto"
 	"go-api-starter/modules/auth/repository"
+	"go-api-starter/pkg/cache"
+	"go-api-starter/pkg/config"
+	"go-api-starter/pkg/database"
+	"go-api-starter/pkg/utils"
+	"sync/atomic"
 
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
 	"github.com/rs/zerolog"
 	"github.com/samber/do/v2"
 )
 
 type AuthService interface {
+	Register(ctx context.Context, req *dto.RegisterRequest) (*dto.RegisterResponse, error)
+	Login(ctx context.Context, req *dto.LoginRequest) (*dto.LoginResponse, error)
+	RefreshToken(ctx context.Context, req *dto.RefreshTokenRequest) (*dto.RefreshTokenResponse, error)
+	Logout(ctx context.Context, req *dto.LogoutRequest) error
+	LogoutAll(ctx context.Context, req *dto.LogoutRequest) error
+	RevokeAllSessions(ctx context.Context, userID uuid.UUID) error
+	UnlockUser(ctx context.Context, userID uuid.UUID) error
+
+	ForgotPassword(ctx context.Context, req *dto.ForgotPasswordRequest) (*dto.ForgotPasswordResponse, error)
+	VerifyOTP(ctx context.Context, req *dto.VerifyOTPRequest) (*dto.VerifyOTPResponse, error)
+	ResetPassword(ctx context.Context, req *dto.ResetPasswordRequest) error
+	RequestChangePasswordOTP(ctx context.Context, userID uuid.UUID) error
+	ChangePassword(ctx context.Context, userID uuid.UUID, req *dto.ChangePasswordRequest) (*dto.ChangePasswordResponse, error)
+
+	RequestEmailVerification(ctx context.Context, req *dto.RequestEmailVerificationRequest) error
+	ConfirmEmailVerification(ctx context.Context, req *dto.ConfirmEmailVerificationRequest) error
+	RequestPhoneVerification(ctx context.Context, req *dto.RequestPhoneVerificationRequest) error
+	ConfirmPhoneVerification(ctx context.Context, req *dto.ConfirmPhoneVerificationRequest) error
+	EnsureVerified(ctx context.Context, userID uuid.UUID) error
+
+	GetUser(ctx context.Context, userID uuid.UUID) (*dto.UserResponse, error)
+	ListUsers(ctx context.Context, params *utils.QueryParams) (*dto.PaginatedUserDTO, error)
+	UpdateUser(ctx context.Context, userID uuid.UUID, req *dto.UserRequest) (*dto.UserResponse, error)
+	DeleteUser(ctx context.Context, userID uuid.UUID) error
+}
+
+// txRunner runs fn in a transaction that repositories pick up from ctx; it is implemented
+// by *database.TxManager.
+type txRunner interface {
+	WithTx(ctx context.Context, fn func(ctx context.Context) error) error
 }
 
 type authService struct {
+	config         *config.Config
 	logger         *zerolog.Logger
+	redis          *redis.Client
+	tokenService   *utils.TokenService
+	otpService     *utils.OTPService
+	smsSender      utils.SMSSender
+	emailConfig    utils.EmailConfig
 	authRepository repository.AuthRepository
+	txManager      txRunner
+	reloader       *config.Reloader
+	// loginLimits holds the reloadable login rate limits of auth config
+	loginLimits atomic.Pointer[config.AuthConfig]
 }
 
 func NewAuthService(i do.Injector) (AuthService, error) {
+	reloader := do.MustInvoke[*config.Reloader](i)
+	config := do.MustInvoke[*config.Config](i)
+	logger := do.MustInvoke[*zerolog.Logger](i)
+	redis := do.MustInvoke[*cache.Redis](i)
+	tokenService := do.MustInvoke[*utils.TokenService](i)
+	smsSender := do.MustInvoke[utils.SMSSender](i)
+	authRepository := do.MustInvoke[repository.AuthRepository](i)
+	txManager := do.MustInvoke[*database.TxManager](i)
+	service := &authService{
+		config:       config,
+		logger:       logger,
+		redis:        redis.Client(),
+		tokenService: tokenService,
+		otpService:   utils.NewOTPService(redis.Client()),
+		smsSender:    smsSender,
+		emailConfig: utils.EmailConfig{
+			Host:     config.Email.Host,
+			Port:     config.Email.Port,
+			Username: config.Email.Username,
+			Password: config.Email.Password,
+			From:     config.Email.From,
+			FromName: config.Email.FromName,
+		},
+		authRepository: authRepository,
+		txManager:      txManager,
+		reloader:       reloader,
+	}
+	service.loginLimits.Store(&config.Auth)
+
+	return service, nil
+}
+
+// OnConfigReload applies the new login rate limits.
+func (s *authService) OnConfigReload(cfg *config.Config) {
+	auth := cfg.Auth
+	s.loginLimits.Store(&auth)
+}
+
+// RBACService manages roles and permissions and resolves the effective permissions of users.
+type RBACService interface {
+	CreateRole(ctx context.Context, req *dto.RoleRequest) (*dto.RoleResponse, error)
+	GetRole(ctx context.Context, roleID uuid.UUID) (*dto.RoleResponse, error)
+	ListRoles(ctx context.Context) ([]dto.RoleResponse, error)
+	UpdateRole(ctx context.Context, roleID uuid.UUID, req *dto.RoleRequest) (*dto.RoleResponse, error)
+	DeleteRole(ctx context.Context, roleID uuid.UUID) error
+	SetRolePermissions(ctx context.Context, roleID uuid.UUID, req *dto.SetRolePermissionsRequest) (*dto.RoleResponse, error)
+
+	CreatePermission(ctx context.Context, req *dto.PermissionRequest) (*dto.PermissionResponse, error)
+	ListPermissions(ctx context.Context) ([]dto.PermissionResponse, error)
+	DeletePermission(ctx context.Context, permissionID uuid.UUID) error
+
+	AssignUserRole(ctx context.Context, userID uuid.UUID, req *dto.AssignRoleRequest) error
+	RemoveUserRole(ctx context.Context, userID uuid.UUID, roleID uuid.UUID) error
+	GetUserAccess(ctx context.Context, userID uuid.UUID) (*dto.UserAccessResponse, error)
+	GetUserRoles(ctx context.Context, userID uuid.UUID) ([]string, error)
+	GetUserPermissions(ctx context.Context, userID uuid.UUID) ([]string, error)
+}
+
+type rbacService struct {
+	logger         *zerolog.Logger
+	redis          *redis.Client
+	authRepository repository.AuthRepository
+}
+
+func NewRBACService(i do.Injector) (RBACService, error) {
 	logger := do.MustInvoke[*zerolog.Logger](i)
+	redis := do.MustInvoke[*cache.Redis](i)
 	authRepository := do.MustInvoke[repository.AuthRepository](i)
-	return &authService{
+	return &rbacService{
 		logger:         logger,
+		redis:          redis.Client(),
 		authRepository: authRepository,
 	}, nil
 }
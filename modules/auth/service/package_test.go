@@ -0,0 +1,420 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go-api-starter/modules/auth/entity"
+	"go-api-starter/modules/auth/repository"
+	"go-api-starter/pkg/config"
+	"go-api-starter/pkg/constants"
+	"go-api-starter/pkg/utils"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog"
+	"github.com/samber/do/v2"
+)
+
+// fakeTxRunner runs fn directly; the fake repository has nothing to roll back.
+type fakeTxRunner struct{}
+
+func (fakeTxRunner) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// fakeAuthRepository keeps users, refresh tokens and RBAC data in memory. Methods the tests do not
+// use are left to the embedded interface and panic when called.
+type fakeAuthRepository struct {
+	repository.AuthRepository
+
+	mu            sync.Mutex
+	users         map[uuid.UUID]entity.User
+	refreshTokens map[uuid.UUID]entity.RefreshToken
+	passwords     map[uuid.UUID][]string
+
+	roles           map[uuid.UUID]entity.Role
+	permissions     map[uuid.UUID]entity.Permission
+	rolePermissions map[uuid.UUID][]uuid.UUID
+	userRoles       map[uuid.UUID][]uuid.UUID
+}
+
+func newFakeAuthRepository() *fakeAuthRepository {
+	return &fakeAuthRepository{
+		users:         make(map[uuid.UUID]entity.User),
+		refreshTokens: make(map[uuid.UUID]entity.RefreshToken),
+		passwords:     make(map[uuid.UUID][]string),
+
+		roles:           make(map[uuid.UUID]entity.Role),
+		permissions:     make(map[uuid.UUID]entity.Permission),
+		rolePermissions: make(map[uuid.UUID][]uuid.UUID),
+		userRoles:       make(map[uuid.UUID][]uuid.UUID),
+	}
+}
+
+func (r *fakeAuthRepository) CreateUser(_ context.Context, user *entity.User) (*entity.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	created := *user
+	created.ID = uuid.New()
+	created.CreatedAt = time.Now()
+	created.UpdatedAt = created.CreatedAt
+	r.users[created.ID] = created
+	return &created, nil
+}
+
+func (r *fakeAuthRepository) GetUserByID(_ context.Context, id uuid.UUID) (*entity.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.users[id]
+	if !ok {
+		return nil, nil
+	}
+	return &user, nil
+}
+
+func (r *fakeAuthRepository) GetUserByIdentifier(_ context.Context, identifier string, identifierType utils.IdentifierType) (*entity.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, user := range r.users {
+		var value *string
+		switch identifierType {
+		case utils.IdentifierTypeEmail:
+			value = user.Email
+		case utils.IdentifierTypePhone:
+			value = user.Phone
+		case utils.IdentifierTypeUsername:
+			value = user.Username
+		}
+		if value != nil && strings.EqualFold(*value, identifier) {
+			return &user, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeAuthRepository) UpdateUserLockedUntil(_ context.Context, id uuid.UUID, lockedUntil *time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user := r.users[id]
+	user.LockedUntil = lockedUntil
+	r.users[id] = user
+	return nil
+}
+
+func (r *fakeAuthRepository) UpdateUserPassword(_ context.Context, id uuid.UUID, hashedPassword string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user := r.users[id]
+	user.Password = hashedPassword
+	r.users[id] = user
+	return nil
+}
+
+func (r *fakeAuthRepository) MarkEmailVerified(_ context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user := r.users[id]
+	now := time.Now()
+	user.EmailVerifiedAt = &now
+	r.users[id] = user
+	return nil
+}
+
+func (r *fakeAuthRepository) CreatePasswordHistory(_ context.Context, userID uuid.UUID, hashedPassword string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.passwords[userID] = append([]string{hashedPassword}, r.passwords[userID]...)
+	return nil
+}
+
+func (r *fakeAuthRepository) GetRecentPasswordHashes(_ context.Context, userID uuid.UUID, limit int) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	hashes := r.passwords[userID]
+	if len(hashes) > limit {
+		hashes = hashes[:limit]
+	}
+	return hashes, nil
+}
+
+func (r *fakeAuthRepository) CreateRefreshToken(_ context.Context, token *entity.RefreshToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	created := *token
+	created.CreatedAt = time.Now()
+	r.refreshTokens[created.ID] = created
+	return nil
+}
+
+func (r *fakeAuthRepository) GetRefreshTokenByID(_ context.Context, id uuid.UUID) (*entity.RefreshToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	token, ok := r.refreshTokens[id]
+	if !ok {
+		return nil, nil
+	}
+	return &token, nil
+}
+
+func (r *fakeAuthRepository) RotateRefreshToken(_ context.Context, id uuid.UUID, replacedBy uuid.UUID) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	token, ok := r.refreshTokens[id]
+	if !ok || token.RevokedAt != nil {
+		return false, nil
+	}
+	now := time.Now()
+	token.RevokedAt = &now
+	token.ReplacedBy = &replacedBy
+	r.refreshTokens[id] = token
+	return true, nil
+}
+
+func (r *fakeAuthRepository) RevokeRefreshTokenFamily(_ context.Context, familyID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for id, token := range r.refreshTokens {
+		if token.FamilyID == familyID && token.RevokedAt == nil {
+			token.RevokedAt = &now
+			r.refreshTokens[id] = token
+		}
+	}
+	return nil
+}
+
+func (r *fakeAuthRepository) RevokeUserRefreshTokens(_ context.Context, userID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for id, token := range r.refreshTokens {
+		if token.UserID == userID && token.RevokedAt == nil {
+			token.RevokedAt = &now
+			r.refreshTokens[id] = token
+		}
+	}
+	return nil
+}
+
+// addRole stores a role and returns its ID.
+func (r *fakeAuthRepository) addRole(slug string) uuid.UUID {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	role := entity.Role{Slug: slug, Name: slug}
+	role.ID = uuid.New()
+	r.roles[role.ID] = role
+	return role.ID
+}
+
+// addPermission stores a permission and returns its ID.
+func (r *fakeAuthRepository) addPermission(slug string) uuid.UUID {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	permission := entity.Permission{Slug: slug, Name: slug}
+	permission.ID = uuid.New()
+	r.permissions[permission.ID] = permission
+	return permission.ID
+}
+
+func (r *fakeAuthRepository) GetRoleByID(_ context.Context, id uuid.UUID) (*entity.Role, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	role, ok := r.roles[id]
+	if !ok {
+		return nil, nil
+	}
+	return &role, nil
+}
+
+func (r *fakeAuthRepository) GetPermissionsByIDs(_ context.Context, ids []uuid.UUID) ([]entity.Permission, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	permissions := make([]entity.Permission, 0, len(ids))
+	for _, id := range ids {
+		if permission, ok := r.permissions[id]; ok {
+			permissions = append(permissions, permission)
+		}
+	}
+	return permissions, nil
+}
+
+func (r *fakeAuthRepository) GetRolePermissions(_ context.Context, roleID uuid.UUID) ([]entity.Permission, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	permissions := make([]entity.Permission, 0)
+	for _, id := range r.rolePermissions[roleID] {
+		permissions = append(permissions, r.permissions[id])
+	}
+	return permissions, nil
+}
+
+func (r *fakeAuthRepository) GetPermissionSlugsByRoleSlug(_ context.Context, roleSlug string) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	slugs := make([]string, 0)
+	for id, role := range r.roles {
+		if role.Slug != roleSlug {
+			continue
+		}
+		for _, permissionID := range r.rolePermissions[id] {
+			slugs = append(slugs, r.permissions[permissionID].Slug)
+		}
+	}
+	return slugs, nil
+}
+
+func (r *fakeAuthRepository) SetRolePermissions(_ context.Context, roleID uuid.UUID, permissionIDs []uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.rolePermissions[roleID] = slices.Clone(permissionIDs)
+	return nil
+}
+
+func (r *fakeAuthRepository) GetUserRoles(_ context.Context, userID uuid.UUID) ([]entity.Role, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	roles := make([]entity.Role, 0)
+	for _, id := range r.userRoles[userID] {
+		roles = append(roles, r.roles[id])
+	}
+	return roles, nil
+}
+
+func (r *fakeAuthRepository) AssignUserRole(_ context.Context, userID uuid.UUID, roleID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !slices.Contains(r.userRoles[userID], roleID) {
+		r.userRoles[userID] = append(r.userRoles[userID], roleID)
+	}
+	return nil
+}
+
+func (r *fakeAuthRepository) RemoveUserRole(_ context.Context, userID uuid.UUID, roleID uuid.UUID) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	index := slices.Index(r.userRoles[userID], roleID)
+	if index < 0 {
+		return false, nil
+	}
+	r.userRoles[userID] = slices.Delete(r.userRoles[userID], index, index+1)
+	return true, nil
+}
+
+// activeRefreshTokens counts the refresh tokens of the user that are not revoked.
+func (r *fakeAuthRepository) activeRefreshTokens(userID uuid.UUID) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for _, token := range r.refreshTokens {
+		if token.UserID == userID && token.RevokedAt == nil {
+			count++
+		}
+	}
+	return count
+}
+
+func newTestConfig() *config.Config {
+	return &config.Config{
+		JWT: config.JWTConfig{
+			Algorithm:                 utils.TokenAlgorithmHS256,
+			Secret:                    "test-secret",
+			Issuer:                    "go-api-starter",
+			AccessTokenTTL:            900,
+			RefreshTokenTTL:           3600,
+			ResetPasswordTokenTTL:     600,
+			EmailVerificationTokenTTL: 3600,
+		},
+		Auth: config.AuthConfig{
+			VerificationPolicy:    constants.VerificationPolicyNone,
+			MaxLoginAttempts:      3,
+			MaxLoginAttemptsPerIP: 20,
+			BlockDuration:         900,
+		},
+	}
+}
+
+// newTestAuthService builds an authService on top of the fake repository and miniredis.
+// modify, when set, adjusts the config before the service reads it.
+func newTestAuthService(t *testing.T, modify func(cfg *config.Config)) (*authService, *fakeAuthRepository, *miniredis.Miniredis) {
+	t.Helper()
+
+	cfg := newTestConfig()
+	if modify != nil {
+		modify(cfg)
+	}
+
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	tokenService, err := utils.NewTokenServiceWithConfig(cfg.JWT, client)
+	if err != nil {
+		t.Fatalf("NewTokenServiceWithConfig: %v", err)
+	}
+
+	logger := zerolog.Nop()
+	injector := do.New()
+	do.ProvideValue(injector, cfg)
+	do.ProvideValue(injector, &logger)
+	reloader, err := config.NewReloader(injector)
+	if err != nil {
+		t.Fatalf("NewReloader: %v", err)
+	}
+
+	repo := newFakeAuthRepository()
+	service := &authService{
+		config:         cfg,
+		logger:         &logger,
+		redis:          client,
+		tokenService:   tokenService,
+		otpService:     utils.NewOTPService(client),
+		authRepository: repo,
+		txManager:      fakeTxRunner{},
+		reloader:       reloader,
+	}
+	service.loginLimits.Store(&cfg.Auth)
+
+	return service, repo, server
+}
+
+// newTestRBACService builds an rbacService on top of the auth service's fake repository and Redis.
+func newTestRBACService(t *testing.T) (*rbacService, *authService, *fakeAuthRepository) {
+	t.Helper()
+
+	authService, repo, _ := newTestAuthService(t, nil)
+	return &rbacService{
+		logger:         authService.logger,
+		redis:          authService.redis,
+		authRepository: repo,
+	}, authService, repo
+}
@@ -0,0 +1,267 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-api-starter/modules/auth/dto"
+	"go-api-starter/modules/auth/entity"
+	"go-api-starter/pkg/apperrors"
+	"go-api-starter/pkg/constants"
+	"go-api-starter/pkg/utils"
+
+	"github.com/google/uuid"
+)
+
+// ForgotPassword sends a reset code to the account of the identifier. The response carries a
+// random challenge ID and is the same whether or not the account exists or the code could be
+// sent, so the endpoint cannot be used to enumerate accounts.
+func (s *authService) ForgotPassword(ctx context.Context, req *dto.ForgotPasswordRequest) (*dto.ForgotPasswordResponse, error) {
+	identifierType := utils.DetectIdentifierType(req.Identifier)
+	if identifierType == utils.IdentifierTypeUnknown {
+		return nil, apperrors.NewAppError(apperrors.ErrInvalidInput, "invalid identifier", nil)
+	}
+
+	if err := s.limitOTPRequest(ctx, req.Identifier, req.ClientIP); err != nil {
+		return nil, err
+	}
+
+	user, err := s.authRepository.GetUserByIdentifier(ctx, req.Identifier, identifierType)
+	if err != nil {
+		return nil, apperrors.NewAppError(apperrors.ErrInternalServer, "failed to process forgot password", err)
+	}
+	if user == nil {
+		s.logger.Info().Ctx(ctx).Str("identifier_type", string(identifierType)).Msg("forgot password requested for unknown user")
+		return &dto.ForgotPasswordResponse{ChallengeID: utils.NewOTPChallengeID()}, nil
+	}
+
+	otp, err := s.otpService.GenerateAndStoreOTP(ctx, forgotPasswordOTPIdentifier(req.Identifier), utils.OTPTypeForgotPassword)
+	if err != nil {
+		return nil, apperrors.NewAppError(apperrors.ErrInternalServer, "failed to process forgot password", err)
+	}
+
+	s.deliverInBackground(ctx, func(ctx context.Context) error {
+		return s.sendOTP(ctx, user, identifierType, otp.Code, "Password reset code")
+	})
+
+	return &dto.ForgotPasswordResponse{ChallengeID: otp.ChallengeID}, nil
+}
+
+// VerifyOTP exchanges a reset code, together with the identifier and challenge ID it was
+// requested for, for a reset password token.
+func (s *authService) VerifyOTP(ctx context.Context, req *dto.VerifyOTPRequest) (*dto.VerifyOTPResponse, error) {
+	if err := s.limitOTPVerification(ctx, req.ClientIP); err != nil {
+		return nil, err
+	}
+
+	valid, err := s.otpService.VerifyOTPChallenge(ctx, forgotPasswordOTPIdentifier(req.Identifier), req.ChallengeID, req.OTP, utils.OTPTypeForgotPassword)
+	if err != nil || !valid {
+		return nil, otpAppError(err, "failed to verify OTP")
+	}
+
+	user, err := s.authRepository.GetUserByIdentifier(ctx, req.Identifier, utils.DetectIdentifierType(req.Identifier))
+	if err != nil {
+		return nil, apperrors.NewAppError(apperrors.ErrInternalServer, "failed to verify OTP", err)
+	}
+	if user == nil {
+		return nil, apperrors.NewAppError(apperrors.ErrInvalidOTP, "invalid or expired OTP", nil)
+	}
+
+	token, _, err := s.tokenService.GenerateToken(user.ID.String(), constants.ScopeTokenResetPassword)
+	if err != nil {
+		return nil, apperrors.NewAppError(apperrors.ErrInternalServer, "failed to verify OTP", err)
+	}
+
+	return &dto.VerifyOTPResponse{Token: token}, nil
+}
+
+// forgotPasswordOTPIdentifier keys reset codes by the identifier the user typed, case-insensitively.
+func forgotPasswordOTPIdentifier(identifier string) string {
+	return strings.ToLower(identifier)
+}
+
+func (s *authService) ResetPassword(ctx context.Context, req *dto.ResetPasswordRequest) error {
+	claims, err := s.tokenService.VerifyToken(ctx, req.Token, constants.ScopeTokenResetPassword)
+	if err != nil {
+		switch {
+		case errors.Is(err, utils.ErrExpiredToken):
+			return apperrors.NewAppError(apperrors.ErrTokenExpired, "reset password token has expired", err)
+		case errors.Is(err, utils.ErrInvalidToken), errors.Is(err, utils.ErrInvalidTokenScope), errors.Is(err, utils.ErrRevokedToken):
+			return apperrors.NewAppError(apperrors.ErrInvalidToken, "invalid reset password token", err)
+		default:
+			return apperrors.NewAppError(apperrors.ErrInternalServer, "failed to reset password", err)
+		}
+	}
+
+	userID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return apperrors.NewAppError(apperrors.ErrInvalidToken, "invalid reset password token", err)
+	}
+
+	user, err := s.authRepository.GetUserByID(ctx, userID)
+	if err != nil {
+		return apperrors.NewAppError(apperrors.ErrInternalServer, "failed to reset password", err)
+	}
+	if user == nil {
+		return apperrors.NewAppError(apperrors.ErrInvalidToken, "invalid reset password token", nil)
+	}
+
+	if err := s.updatePassword(ctx, user, req.NewPassword); err != nil {
+		return err
+	}
+
+	// The reset token is single use
+	if err := s.tokenService.RevokeToken(ctx, claims); err != nil {
+		return apperrors.NewAppError(apperrors.ErrInternalServer, "failed to reset password", err)
+	}
+
+	return s.RevokeAllSessions(ctx, user.ID)
+}
+
+func (s *authService) RequestChangePasswordOTP(ctx context.Context, userID uuid.UUID) error {
+	user, err := s.getActiveUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.limitOTPRequest(ctx, user.ID.String(), ""); err != nil {
+		return err
+	}
+
+	otp, err := s.otpService.GenerateAndStoreOTP(ctx, user.ID.String(), utils.OTPTypeChangePassword)
+	if err != nil {
+		return apperrors.NewAppError(apperrors.ErrInternalServer, "failed to send OTP", err)
+	}
+
+	// The step-up code only goes to a channel the user has proven to own
+	switch {
+	case user.Email != nil && user.EmailVerifiedAt != nil:
+		err = s.sendOTPByEmail(*user.Email, "Change password code", otp.Code)
+	case user.Phone != nil && user.PhoneVerifiedAt != nil:
+		err = s.sendOTPBySMS(ctx, *user.Phone, "Change password code", otp.Code)
+	default:
+		_ = s.otpService.DeleteOTP(ctx, user.ID.String(), utils.OTPTypeChangePassword)
+		return apperrors.NewAppError(apperrors.ErrNoVerifiedChannel, "no verified email or phone to receive the OTP", nil)
+	}
+	if err != nil {
+		return apperrors.NewAppError(apperrors.ErrInternalServer, "failed to send OTP", err)
+	}
+
+	return nil
+}
+
+func (s *authService) ChangePassword(ctx context.Context, userID uuid.UUID, req *dto.ChangePasswordRequest) (*dto.ChangePasswordResponse, error) {
+	user, err := s.getActiveUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.LockedUntil != nil && user.LockedUntil.After(time.Now()) {
+		return nil, newRetryAfterError(apperrors.ErrAccountLocked, "account is temporarily locked, please try again later", time.Until(*user.LockedUntil))
+	}
+
+	// The single-use OTP is checked before the password, so a stolen session cannot test
+	// passwords without a fresh code from the user's verified channel.
+	valid, err := s.otpService.VerifyOTP(ctx, user.ID.String(), req.OTP, utils.OTPTypeChangePassword)
+	if err != nil || !valid {
+		return nil, otpAppError(err, "failed to change password")
+	}
+
+	if !utils.ComparePassword(user.Password, req.Password) {
+		if err := s.recordFailedPassword(ctx, user); err != nil {
+			return nil, apperrors.NewAppError(apperrors.ErrInternalServer, "failed to change password", err)
+		}
+		return nil, apperrors.NewAppError(apperrors.ErrIncorrectPassword, "current password is incorrect", nil)
+	}
+	if err := s.redis.Del(ctx, passwordAttemptsKey(user.ID)).Err(); err != nil {
+		return nil, apperrors.NewAppError(apperrors.ErrInternalServer, "failed to change password", err)
+	}
+
+	if err := s.updatePassword(ctx, user, req.NewPassword); err != nil {
+		return nil, err
+	}
+
+	// Sign every session out, then hand the caller a fresh pair so only this client stays logged in
+	if err := s.RevokeAllSessions(ctx, user.ID); err != nil {
+		return nil, err
+	}
+
+	accessToken, refreshToken, err := s.issueTokenPair(ctx, user.ID, uuid.New(), nil)
+	if err != nil {
+		return nil, apperrors.NewAppError(apperrors.ErrInternalServer, "failed to change password", err)
+	}
+
+	return &dto.ChangePasswordResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+// updatePassword replaces the user's password, rejecting the current one and the
+// last PasswordHistoryLimit passwords.
+func (s *authService) updatePassword(ctx context.Context, user *entity.User, newPassword string) error {
+	if utils.ComparePassword(user.Password, newPassword) {
+		return apperrors.NewAppError(apperrors.ErrPasswordReused, "new password must be different from recent passwords", nil)
+	}
+
+	recentHashes, err := s.authRepository.GetRecentPasswordHashes(ctx, user.ID, constants.PasswordHistoryLimit)
+	if err != nil {
+		return apperrors.NewAppError(apperrors.ErrInternalServer, "failed to update password", err)
+	}
+	for _, hash := range recentHashes {
+		if utils.ComparePassword(hash, newPassword) {
+			return apperrors.NewAppError(apperrors.ErrPasswordReused, "new password must be different from recent passwords", nil)
+		}
+	}
+
+	hashedPassword, err := utils.HashPassword(newPassword)
+	if err != nil {
+		return apperrors.NewAppError(apperrors.ErrInternalServer, "failed to update password", err)
+	}
+
+	// The old hash only moves to the history if the new one is stored, so reuse checks stay complete
+	err = s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		if err := s.authRepository.UpdateUserPassword(ctx, user.ID, hashedPassword); err != nil {
+			return err
+		}
+		return s.authRepository.CreatePasswordHistory(ctx, user.ID, user.Password)
+	})
+	if err != nil {
+		return apperrors.NewAppError(apperrors.ErrInternalServer, "failed to update password", err)
+	}
+
+	return nil
+}
+
+// sendOTP delivers an OTP code to the user. The channel follows the identifier the user typed;
+// a username falls back to the email address first, then the phone number.
+func (s *authService) sendOTP(ctx context.Context, user *entity.User, identifierType utils.IdentifierType, code string, subject string) error {
+	useEmail := user.Email != nil && (identifierType != utils.IdentifierTypePhone || user.Phone == nil)
+	switch {
+	case useEmail:
+		return s.sendOTPByEmail(*user.Email, subject, code)
+	case user.Phone != nil:
+		return s.sendOTPBySMS(ctx, *user.Phone, subject, code)
+	default:
+		return fmt.Errorf("user has no email or phone to receive the OTP")
+	}
+}
+
+func otpMessage(subject string, code string) string {
+	return fmt.Sprintf("%s: %s. The code expires in %d minutes.", subject, code, int(utils.OTPExpiry.Minutes()))
+}
+
+func (s *authService) sendOTPByEmail(email string, subject string, code string) error {
+	return utils.SendEmailTLS(s.emailConfig, utils.EmailMessage{
+		To:      []string{email},
+		Subject: subject,
+		Body:    otpMessage(subject, code),
+	})
+}
+
+func (s *authService) sendOTPBySMS(ctx context.Context, phone string, subject string, code string) error {
+	return s.smsSender.SendSMS(ctx, phone, otpMessage(subject, code))
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-api-starter/modules/auth/dto"
+	"go-api-starter/modules/auth/entity"
+	"go-api-starter/pkg/apperrors"
+	"go-api-starter/pkg/utils"
+)
+
+const testNewPassword = "N3w!Password"
+
+// changePasswordOTP stores a change password code for the user, as RequestChangePasswordOTP does.
+func changePasswordOTP(t *testing.T, service *authService, user *entity.User) string {
+	t.Helper()
+
+	otp, err := service.otpService.GenerateAndStoreOTP(context.Background(), user.ID.String(), utils.OTPTypeChangePassword)
+	if err != nil {
+		t.Fatalf("GenerateAndStoreOTP: %v", err)
+	}
+	return otp.Code
+}
+
+func TestAuthServiceChangePassword(t *testing.T) {
+	ctx := context.Background()
+	service, repo, _ := newTestAuthService(t, nil)
+	user := registerTestUser(t, service, repo, "alice@example.com")
+
+	response, err := service.ChangePassword(ctx, user.ID, &dto.ChangePasswordRequest{
+		Password:    testPassword,
+		NewPassword: testNewPassword,
+		OTP:         changePasswordOTP(t, service, user),
+	})
+	if err != nil {
+		t.Fatalf("ChangePassword: %v", err)
+	}
+	if response.AccessToken == "" || response.RefreshToken == "" {
+		t.Fatalf("ChangePassword response = %+v, want a token pair", response)
+	}
+
+	changed, _ := repo.GetUserByID(ctx, user.ID)
+	if !utils.ComparePassword(changed.Password, testNewPassword) {
+		t.Fatal("password was not changed")
+	}
+	// The session from Register is signed out, only the returned one is left
+	if got := repo.activeRefreshTokens(user.ID); got != 1 {
+		t.Fatalf("active refresh tokens = %d, want 1", got)
+	}
+
+	_, err = service.ChangePassword(ctx, user.ID, &dto.ChangePasswordRequest{
+		Password:    testNewPassword,
+		NewPassword: testPassword,
+		OTP:         changePasswordOTP(t, service, user),
+	})
+	assertErrorCode(t, err, apperrors.ErrPasswordReused)
+}
+
+func TestAuthServiceChangePasswordChecksOTPFirst(t *testing.T) {
+	ctx := context.Background()
+	service, repo, server := newTestAuthService(t, nil)
+	user := registerTestUser(t, service, repo, "alice@example.com")
+	code := changePasswordOTP(t, service, user)
+
+	wrongCode := "000000"
+	if code == wrongCode {
+		wrongCode = "111111"
+	}
+	_, err := service.ChangePassword(ctx, user.ID, &dto.ChangePasswordRequest{
+		Password:    "wrong-password",
+		NewPassword: testNewPassword,
+		OTP:         wrongCode,
+	})
+	assertErrorCode(t, err, apperrors.ErrInvalidOTP)
+
+	// Without a valid code the password was never compared, so nothing was counted against it
+	if server.Exists(passwordAttemptsKey(user.ID)) {
+		t.Fatal("a wrong password was counted without a valid OTP")
+	}
+}
+
+func TestAuthServiceChangePasswordLockout(t *testing.T) {
+	ctx := context.Background()
+	service, repo, server := newTestAuthService(t, nil)
+	user := registerTestUser(t, service, repo, "alice@example.com")
+
+	for range service.config.Auth.MaxLoginAttempts {
+		_, err := service.ChangePassword(ctx, user.ID, &dto.ChangePasswordRequest{
+			Password:    "wrong-password",
+			NewPassword: testNewPassword,
+			OTP:         changePasswordOTP(t, service, user),
+		})
+		assertErrorCode(t, err, apperrors.ErrIncorrectPassword)
+	}
+
+	locked, _ := repo.GetUserByID(ctx, user.ID)
+	if locked.LockedUntil == nil || !locked.LockedUntil.After(time.Now()) {
+		t.Fatalf("LockedUntil = %v, want a time in the future", locked.LockedUntil)
+	}
+
+	_, err := service.ChangePassword(ctx, user.ID, &dto.ChangePasswordRequest{
+		Password:    testPassword,
+		NewPassword: testNewPassword,
+		OTP:         changePasswordOTP(t, service, user),
+	})
+	appErr := assertErrorCode(t, err, apperrors.ErrAccountLocked)
+	if details, ok := appErr.Details.(dto.LoginBlockedDetails); !ok || details.RetryAfter <= 0 {
+		t.Fatalf("details = %#v, want a positive retry-after", appErr.Details)
+	}
+
+	_, err = service.Login(ctx, &dto.LoginRequest{Identifier: "alice@example.com", Password: testPassword})
+	assertErrorCode(t, err, apperrors.ErrAccountLocked)
+
+	if err := service.UnlockUser(ctx, user.ID); err != nil {
+		t.Fatalf("UnlockUser: %v", err)
+	}
+	if server.Exists(passwordAttemptsKey(user.ID)) {
+		t.Fatal("UnlockUser kept the wrong password count")
+	}
+	if _, err := service.ChangePassword(ctx, user.ID, &dto.ChangePasswordRequest{
+		Password:    testPassword,
+		NewPassword: testNewPassword,
+		OTP:         changePasswordOTP(t, service, user),
+	}); err != nil {
+		t.Fatalf("ChangePassword after unlock: %v", err)
+	}
+}
@@ -0,0 +1,378 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"go-api-starter/modules/auth/dto"
+	"go-api-starter/modules/auth/mapper"
+	"go-api-starter/pkg/apperrors"
+	"go-api-starter/pkg/constants"
+	"go-api-starter/pkg/utils"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+func (s *rbacService) CreateRole(ctx context.Context, req *dto.RoleRequest) (*dto.RoleResponse, error) {
+	role, err := s.authRepository.CreateRole(ctx, mapper.ToRoleEntity(req))
+	if err != nil {
+		return nil, asAppError(err, "failed to create role")
+	}
+
+	return mapper.ToRoleResponse(role, nil), nil
+}
+
+func (s *rbacService) GetRole(ctx context.Context, roleID uuid.UUID) (*dto.RoleResponse, error) {
+	role, err := s.authRepository.GetRoleByID(ctx, roleID)
+	if err != nil {
+		return nil, apperrors.NewAppError(apperrors.ErrInternalServer, "failed to get role", err)
+	}
+	if role == nil {
+		return nil, apperrors.NewAppError(apperrors.ErrNotFound, "role not found", nil)
+	}
+
+	permissions, err := s.authRepository.GetRolePermissions(ctx, roleID)
+	if err != nil {
+		return nil, apperrors.NewAppError(apperrors.ErrInternalServer, "failed to get role", err)
+	}
+
+	return mapper.ToRoleResponse(role, permissions), nil
+}
+
+func (s *rbacService) ListRoles(ctx context.Context) ([]dto.RoleResponse, error) {
+	roles, err := s.authRepository.ListRoles(ctx)
+	if err != nil {
+		return nil, apperrors.NewAppError(apperrors.ErrInternalServer, "failed to list roles", err)
+	}
+
+	return mapper.ToRoleResponses(roles), nil
+}
+
+func (s *rbacService) UpdateRole(ctx context.Context, roleID uuid.UUID, req *dto.RoleRequest) (*dto.RoleResponse, error) {
+	existing, err := s.authRepository.GetRoleByID(ctx, roleID)
+	if err != nil {
+		return nil, apperrors.NewAppError(apperrors.ErrInternalServer, "failed to update role", err)
+	}
+	if existing == nil {
+		return nil, apperrors.NewAppError(apperrors.ErrNotFound, "role not found", nil)
+	}
+
+	role := mapper.ToRoleEntity(req)
+	role.ID = roleID
+
+	updated, err := s.authRepository.UpdateRole(ctx, role)
+	if err != nil {
+		return nil, asAppError(err, "failed to update role")
+	}
+	if updated == nil {
+		return nil, apperrors.NewAppError(apperrors.ErrNotFound, "role not found", nil)
+	}
+
+	// Cached role lists hold slugs, so a renamed role makes every cached entry stale
+	if existing.Slug != updated.Slug {
+		if err := s.bumpCacheVersion(ctx); err != nil {
+			return nil, apperrors.NewAppError(apperrors.ErrInternalServer, "failed to update role", err)
+		}
+	}
+
+	return mapper.ToRoleResponse(updated, nil), nil
+}
+
+func (s *rbacService) DeleteRole(ctx context.Context, roleID uuid.UUID) error {
+	role, err := s.authRepository.GetRoleByID(ctx, roleID)
+	if err != nil {
+		return apperrors.NewAppError(apperrors.ErrInternalServer, "failed to delete role", err)
+	}
+	if role == nil {
+		return apperrors.NewAppError(apperrors.ErrNotFound, "role not found", nil)
+	}
+
+	deleted, err := s.authRepository.DeleteRole(ctx, roleID)
+	if err != nil {
+		return apperrors.NewAppError(apperrors.ErrInternalServer, "failed to delete role", err)
+	}
+	if !deleted {
+		return apperrors.NewAppError(apperrors.ErrNotFound, "role not found", nil)
+	}
+
+	if err := s.bumpCacheVersion(ctx); err != nil {
+		return apperrors.NewAppError(apperrors.ErrInternalServer, "failed to delete role", err)
+	}
+
+	return nil
+}
+
+func (s *rbacService) SetRolePermissions(ctx context.Context, roleID uuid.UUID, req *dto.SetRolePermissionsRequest) (*dto.RoleResponse, error) {
+	role, err := s.authRepository.GetRoleByID(ctx, roleID)
+	if err != nil {
+		return nil, apperrors.NewAppError(apperrors.ErrInternalServer, "failed to set role permissions", err)
+	}
+	if role == nil {
+		return nil, apperrors.NewAppError(apperrors.ErrNotFound, "role not found", nil)
+	}
+
+	permissionIDs := slices.Clone(req.PermissionIDs)
+	slices.SortFunc(permissionIDs, func(a, b uuid.UUID) int { return slices.Compare(a[:], b[:]) })
+	permissionIDs = slices.Compact(permissionIDs)
+
+	if len(permissionIDs) > 0 {
+		permissions, err := s.authRepository.GetPermissionsByIDs(ctx, permissionIDs)
+		if err != nil {
+			return nil, apperrors.NewAppError(apperrors.ErrInternalServer, "failed to set role permissions", err)
+		}
+		if len(permissions) != len(permissionIDs) {
+			return nil, apperrors.NewAppError(apperrors.ErrNotFound, "permission not found", nil)
+		}
+	}
+
+	if err := s.authRepository.SetRolePermissions(ctx, roleID, permissionIDs); err != nil {
+		return nil, apperrors.NewAppError(apperrors.ErrInternalServer, "failed to set role permissions", err)
+	}
+
+	if err := s.bumpCacheVersion(ctx); err != nil {
+		return nil, apperrors.NewAppError(apperrors.ErrInternalServer, "failed to set role permissions", err)
+	}
+
+	return s.GetRole(ctx, roleID)
+}
+
+func (s *rbacService) CreatePermission(ctx context.Context, req *dto.PermissionRequest) (*dto.PermissionResponse, error) {
+	permission, err := s.authRepository.CreatePermission(ctx, mapper.ToPermissionEntity(req))
+	if err != nil {
+		return nil, asAppError(err, "failed to create permission")
+	}
+
+	return mapper.ToPermissionResponse(permission), nil
+}
+
+func (s *rbacService) ListPermissions(ctx context.Context) ([]dto.PermissionResponse, error) {
+	permissions, err := s.authRepository.ListPermissions(ctx)
+	if err != nil {
+		return nil, apperrors.NewAppError(apperrors.ErrInternalServer, "failed to list permissions", err)
+	}
+
+	return mapper.ToPermissionResponses(permissions), nil
+}
+
+func (s *rbacService) DeletePermission(ctx context.Context, permissionID uuid.UUID) error {
+	deleted, err := s.authRepository.DeletePermission(ctx, permissionID)
+	if err != nil {
+		return apperrors.NewAppError(apperrors.ErrInternalServer, "failed to delete permission", err)
+	}
+	if !deleted {
+		return apperrors.NewAppError(apperrors.ErrNotFound, "permission not found", nil)
+	}
+
+	if err := s.bumpCacheVersion(ctx); err != nil {
+		return apperrors.NewAppError(apperrors.ErrInternalServer, "failed to delete permission", err)
+	}
+
+	return nil
+}
+
+func (s *rbacService) AssignUserRole(ctx context.Context, userID uuid.UUID, req *dto.AssignRoleRequest) error {
+	user, err := s.authRepository.GetUserByID(ctx, userID)
+	if err != nil {
+		return apperrors.NewAppError(apperrors.ErrInternalServer, "failed to assign role", err)
+	}
+	if user == nil {
+		return apperrors.NewAppError(apperrors.ErrNotFound, "user not found", nil)
+	}
+
+	role, err := s.authRepository.GetRoleByID(ctx, req.RoleID)
+	if err != nil {
+		return apperrors.NewAppError(apperrors.ErrInternalServer, "failed to assign role", err)
+	}
+	if role == nil {
+		return apperrors.NewAppError(apperrors.ErrNotFound, "role not found", nil)
+	}
+
+	if err := s.authRepository.AssignUserRole(ctx, userID, req.RoleID); err != nil {
+		return apperrors.NewAppError(apperrors.ErrInternalServer, "failed to assign role", err)
+	}
+
+	if err := s.bumpCacheVersion(ctx); err != nil {
+		return apperrors.NewAppError(apperrors.ErrInternalServer, "failed to assign role", err)
+	}
+
+	return nil
+}
+
+func (s *rbacService) RemoveUserRole(ctx context.Context, userID uuid.UUID, roleID uuid.UUID) error {
+	removed, err := s.authRepository.RemoveUserRole(ctx, userID, roleID)
+	if err != nil {
+		return apperrors.NewAppError(apperrors.ErrInternalServer, "failed to remove role", err)
+	}
+	if !removed {
+		return apperrors.NewAppError(apperrors.ErrNotFound, "role is not assigned to user", nil)
+	}
+
+	if err := s.bumpCacheVersion(ctx); err != nil {
+		return apperrors.NewAppError(apperrors.ErrInternalServer, "failed to remove role", err)
+	}
+
+	return nil
+}
+
+func (s *rbacService) GetUserAccess(ctx context.Context, userID uuid.UUID) (*dto.UserAccessResponse, error) {
+	user, err := s.authRepository.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, apperrors.NewAppError(apperrors.ErrInternalServer, "failed to get user access", err)
+	}
+	if user == nil {
+		return nil, apperrors.NewAppError(apperrors.ErrNotFound, "user not found", nil)
+	}
+
+	roles, err := s.GetUserRoles(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	permissions, err := s.GetUserPermissions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.UserAccessResponse{Roles: roles, Permissions: permissions}, nil
+}
+
+// GetUserRoles returns the slugs of the roles assigned to a user.
+func (s *rbacService) GetUserRoles(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	return s.getUserRoles(ctx, s.cacheVersion(ctx), userID)
+}
+
+// GetUserPermissions returns the union of the permissions granted by all roles of a user.
+func (s *rbacService) GetUserPermissions(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	version := s.cacheVersion(ctx)
+	key := cacheKey(version, utils.GenerateUserPermissionsKey(userID.String()))
+	if permissions, ok := s.getCachedSlugs(ctx, key); ok {
+		return permissions, nil
+	}
+
+	roles, err := s.getUserRoles(ctx, version, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	permissions := make([]string, 0)
+	for _, role := range roles {
+		rolePermissions, err := s.getRolePermissions(ctx, version, role)
+		if err != nil {
+			return nil, err
+		}
+		permissions = append(permissions, rolePermissions...)
+	}
+	slices.Sort(permissions)
+	permissions = slices.Compact(permissions)
+
+	s.setCachedSlugs(ctx, key, permissions)
+	return permissions, nil
+}
+
+func (s *rbacService) getUserRoles(ctx context.Context, version string, userID uuid.UUID) ([]string, error) {
+	key := cacheKey(version, utils.GenerateUserRolesKey(userID.String()))
+	if roles, ok := s.getCachedSlugs(ctx, key); ok {
+		return roles, nil
+	}
+
+	roles, err := s.authRepository.GetUserRoles(ctx, userID)
+	if err != nil {
+		return nil, apperrors.NewAppError(apperrors.ErrInternalServer, "failed to get user roles", err)
+	}
+
+	slugs := make([]string, 0, len(roles))
+	for _, role := range roles {
+		slugs = append(slugs, role.Slug)
+	}
+
+	s.setCachedSlugs(ctx, key, slugs)
+	return slugs, nil
+}
+
+func (s *rbacService) getRolePermissions(ctx context.Context, version string, roleSlug string) ([]string, error) {
+	key := cacheKey(version, utils.GenerateRolePermissionsKey(roleSlug))
+	if permissions, ok := s.getCachedSlugs(ctx, key); ok {
+		return permissions, nil
+	}
+
+	permissions, err := s.authRepository.GetPermissionSlugsByRoleSlug(ctx, roleSlug)
+	if err != nil {
+		return nil, apperrors.NewAppError(apperrors.ErrInternalServer, "failed to get role permissions", err)
+	}
+
+	s.setCachedSlugs(ctx, key, permissions)
+	return permissions, nil
+}
+
+// cacheVersion returns the current version of the RBAC cache, which is part of every cache key.
+// It is read before the database, and every change bumps it after it is stored, so a read racing
+// with a change can only fill an entry of a version no one reads any more. An empty version,
+// returned when Redis is unavailable, skips the cache.
+func (s *rbacService) cacheVersion(ctx context.Context) string {
+	version, err := s.redis.Get(ctx, constants.RedisKeyRBACVersion).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return "0"
+		}
+		s.logger.Warn().Ctx(ctx).Err(err).Msg("failed to read rbac cache version")
+		return ""
+	}
+	return version
+}
+
+// bumpCacheVersion makes every cached entry stale. Changes are rare, so a change to one role
+// or user drops the cache of all of them. Unlike reads and writes of the cache, a failure here
+// is returned: the stale entries would keep granting revoked access until they expire.
+func (s *rbacService) bumpCacheVersion(ctx context.Context) error {
+	if err := s.redis.Incr(ctx, constants.RedisKeyRBACVersion).Err(); err != nil {
+		s.logger.Error().Ctx(ctx).Err(err).Msg("failed to invalidate rbac cache")
+		return fmt.Errorf("failed to invalidate rbac cache: %w", err)
+	}
+	return nil
+}
+
+// cacheKey returns key under the given cache version, or "" when the cache is skipped.
+func cacheKey(version string, key string) string {
+	if version == "" {
+		return ""
+	}
+	return key + ":v" + version
+}
+
+// getCachedSlugs reads a JSON encoded slug list. Cache failures are logged and treated as a miss
+// so that Redis being unavailable degrades to database lookups instead of failing requests.
+func (s *rbacService) getCachedSlugs(ctx context.Context, key string) ([]string, bool) {
+	if key == "" {
+		return nil, false
+	}
+	raw, err := s.redis.Get(ctx, key).Bytes()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			s.logger.Warn().Ctx(ctx).Err(err).Str("key", key).Msg("failed to read rbac cache")
+		}
+		return nil, false
+	}
+
+	var slugs []string
+	if err := json.Unmarshal(raw, &slugs); err != nil {
+		s.logger.Warn().Ctx(ctx).Err(err).Str("key", key).Msg("failed to decode rbac cache")
+		return nil, false
+	}
+	return slugs, true
+}
+
+func (s *rbacService) setCachedSlugs(ctx context.Context, key string, slugs []string) {
+	if key == "" {
+		return
+	}
+	raw, err := json.Marshal(slugs)
+	if err != nil {
+		return
+	}
+	if err := s.redis.Set(ctx, key, raw, constants.RBACCacheTTL).Err(); err != nil {
+		s.logger.Warn().Ctx(ctx).Err(err).Str("key", key).Msg("failed to write rbac cache")
+	}
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"go-api-starter/modules/auth/dto"
+	"go-api-starter/pkg/utils"
+
+	"github.com/google/uuid"
+)
+
+func assertSlugs(t *testing.T, got []string, want ...string) {
+	t.Helper()
+
+	if want == nil {
+		want = []string{}
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("slugs = %v, want %v", got, want)
+	}
+}
+
+func TestRBACServiceCache(t *testing.T) {
+	ctx := context.Background()
+	service, authService, repo := newTestRBACService(t)
+	user := registerTestUser(t, authService, repo, "alice@example.com")
+
+	roleID := repo.addRole("editor")
+	readID := repo.addPermission("posts.read")
+	writeID := repo.addPermission("posts.write")
+	if _, err := service.SetRolePermissions(ctx, roleID, &dto.SetRolePermissionsRequest{PermissionIDs: []uuid.UUID{readID}}); err != nil {
+		t.Fatalf("SetRolePermissions: %v", err)
+	}
+	if err := service.AssignUserRole(ctx, user.ID, &dto.AssignRoleRequest{RoleID: roleID}); err != nil {
+		t.Fatalf("AssignUserRole: %v", err)
+	}
+
+	permissions, err := service.GetUserPermissions(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("GetUserPermissions: %v", err)
+	}
+	assertSlugs(t, permissions, "posts.read")
+
+	// Served from the cache while nothing changes
+	repo.mu.Lock()
+	repo.rolePermissions[roleID] = []uuid.UUID{readID, writeID}
+	repo.mu.Unlock()
+	permissions, _ = service.GetUserPermissions(ctx, user.ID)
+	assertSlugs(t, permissions, "posts.read")
+
+	if _, err := service.SetRolePermissions(ctx, roleID, &dto.SetRolePermissionsRequest{PermissionIDs: []uuid.UUID{writeID}}); err != nil {
+		t.Fatalf("SetRolePermissions: %v", err)
+	}
+	permissions, _ = service.GetUserPermissions(ctx, user.ID)
+	assertSlugs(t, permissions, "posts.write")
+
+	if err := service.RemoveUserRole(ctx, user.ID, roleID); err != nil {
+		t.Fatalf("RemoveUserRole: %v", err)
+	}
+	permissions, _ = service.GetUserPermissions(ctx, user.ID)
+	assertSlugs(t, permissions)
+	roles, _ := service.GetUserRoles(ctx, user.ID)
+	assertSlugs(t, roles)
+}
+
+func TestRBACServiceCacheReadRacingChange(t *testing.T) {
+	ctx := context.Background()
+	service, authService, repo := newTestRBACService(t)
+	user := registerTestUser(t, authService, repo, "alice@example.com")
+
+	roleID := repo.addRole("admin")
+	if err := service.AssignUserRole(ctx, user.ID, &dto.AssignRoleRequest{RoleID: roleID}); err != nil {
+		t.Fatalf("AssignUserRole: %v", err)
+	}
+
+	// A read takes the version and loads the role from the database before it is removed...
+	version := service.cacheVersion(ctx)
+	if err := service.RemoveUserRole(ctx, user.ID, roleID); err != nil {
+		t.Fatalf("RemoveUserRole: %v", err)
+	}
+	// ...and only fills the cache after the change was invalidated
+	service.setCachedSlugs(ctx, cacheKey(version, utils.GenerateUserRolesKey(user.ID.String())), []string{"admin"})
+
+	roles, err := service.GetUserRoles(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("GetUserRoles: %v", err)
+	}
+	assertSlugs(t, roles)
+}
+
+func TestRBACServiceCacheUnavailable(t *testing.T) {
+	ctx := context.Background()
+	service, authService, repo := newTestRBACService(t)
+	user := registerTestUser(t, authService, repo, "alice@example.com")
+	roleID := repo.addRole("editor")
+	if err := service.AssignUserRole(ctx, user.ID, &dto.AssignRoleRequest{RoleID: roleID}); err != nil {
+		t.Fatalf("AssignUserRole: %v", err)
+	}
+
+	// Reads fall back to the database, changes fail rather than leave stale access cached
+	_ = service.redis.Close()
+	roles, err := service.GetUserRoles(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("GetUserRoles: %v", err)
+	}
+	assertSlugs(t, roles, "editor")
+
+	if err := service.RemoveUserRole(ctx, user.ID, roleID); err == nil {
+		t.Fatal("RemoveUserRole succeeded without invalidating the cache")
+	}
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"go-api-starter/modules/auth/dto"
+	"go-api-starter/modules/auth/mapper"
+	"go-api-starter/pkg/apperrors"
+	"go-api-starter/pkg/utils"
+
+	"github.com/google/uuid"
+)
+
+// asAppError returns err unchanged when it already carries an application error code,
+// otherwise it wraps it as an internal server error with the given message.
+func asAppError(err error, message string) error {
+	var appErr *apperrors.AppError
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	return apperrors.NewAppError(apperrors.ErrInternalServer, message, err)
+}
+
+func (s *authService) GetUser(ctx context.Context, userID uuid.UUID) (*dto.UserResponse, error) {
+	user, err := s.authRepository.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, apperrors.NewAppError(apperrors.ErrInternalServer, "failed to get user", err)
+	}
+	if user == nil {
+		return nil, apperrors.NewAppError(apperrors.ErrNotFound, "user not found", nil)
+	}
+
+	return mapper.ToUserResponse(user), nil
+}
+
+func (s *authService) ListUsers(ctx context.Context, params *utils.QueryParams) (*dto.PaginatedUserDTO, error) {
+	page, err := s.authRepository.ListUsers(ctx, params)
+	if err != nil {
+		return nil, apperrors.NewAppError(apperrors.ErrInternalServer, "failed to list users", err)
+	}
+
+	return mapper.ToPaginatedUserDTO(page), nil
+}
+
+func (s *authService) UpdateUser(ctx context.Context, userID uuid.UUID, req *dto.UserRequest) (*dto.UserResponse, error) {
+	user, err := s.authRepository.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, apperrors.NewAppError(apperrors.ErrInternalServer, "failed to update user", err)
+	}
+	if user == nil {
+		return nil, apperrors.NewAppError(apperrors.ErrNotFound, "user not found", nil)
+	}
+
+	previousEmail, previousPhone := user.Email, user.Phone
+	user = mapper.ToUserEntity(user, req)
+
+	// A changed address has not been proven yet
+	if !equalStringPointers(previousEmail, user.Email) {
+		user.EmailVerifiedAt = nil
+	}
+	if !equalStringPointers(previousPhone, user.Phone) {
+		user.PhoneVerifiedAt = nil
+	}
+
+	updated, err := s.authRepository.UpdateUser(ctx, user)
+	if err != nil {
+		return nil, asAppError(err, "failed to update user")
+	}
+	if updated == nil {
+		return nil, apperrors.NewAppError(apperrors.ErrNotFound, "user not found", nil)
+	}
+
+	if !updated.IsActive {
+		if err := s.tokenService.RevokeAllUserTokens(ctx, updated.ID.String()); err != nil {
+			return nil, apperrors.NewAppError(apperrors.ErrInternalServer, "failed to update user", err)
+		}
+	}
+
+	return mapper.ToUserResponse(updated), nil
+}
+
+func (s *authService) DeleteUser(ctx context.Context, userID uuid.UUID) error {
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		deleted, err := s.authRepository.DeleteUser(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if !deleted {
+			return apperrors.NewAppError(apperrors.ErrNotFound, "user not found", nil)
+		}
+
+		return s.authRepository.RevokeUserRefreshTokens(ctx, userID)
+	})
+	if err != nil {
+		return asAppError(err, "failed to delete user")
+	}
+
+	if err := s.tokenService.RevokeAllUserTokens(ctx, userID.String()); err != nil {
+		return apperrors.NewAppError(apperrors.ErrInternalServer, "failed to delete user", err)
+	}
+
+	return nil
+}
+
+func equalStringPointers(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
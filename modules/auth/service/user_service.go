@@ -1,2 +1,365 @@
 package service
 
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go-api-starter/modules/auth/dto"
+	"go-api-starter/modules/auth/entity"
+	"go-api-starter/pkg/apperrors"
+	"go-api-starter/pkg/constants"
+	"go-api-starter/pkg/utils"
+
+	"github.com/google/uuid"
+)
+
+var errRefreshTokenReused = errors.New("refresh token reused")
+
+func (s *authService) Register(ctx context.Context, req *dto.RegisterRequest) (*dto.RegisterResponse, error) {
+	if s.reloader.FeatureEnabled(constants.FeatureDisableRegistration) {
+		return nil, apperrors.NewAppError(apperrors.ErrForbidden, "registration is currently closed", nil)
+	}
+
+	identifierType := utils.DetectIdentifierType(req.Identifier)
+	if identifierType == utils.IdentifierTypeUnknown {
+		return nil, apperrors.NewAppError(apperrors.ErrInvalidInput, "invalid identifier", nil)
+	}
+	// A username has nothing to verify, so such an account could never pass the policy
+	if identifierType == utils.IdentifierTypeUsername && s.verificationRequired() {
+		return nil, apperrors.NewAppError(apperrors.ErrInvalidInput, "please register with an email or phone", nil)
+	}
+
+	existing, err := s.authRepository.GetUserByIdentifier(ctx, req.Identifier, identifierType)
+	if err != nil {
+		return nil, apperrors.NewAppError(apperrors.ErrInternalServer, "failed to register user", err)
+	}
+	if existing != nil {
+		return nil, apperrors.NewAppError(apperrors.ErrAlreadyExists, "user already exists", nil)
+	}
+
+	hashedPassword, err := utils.HashPassword(req.Password)
+	if err != nil {
+		return nil, apperrors.NewAppError(apperrors.ErrInternalServer, "failed to register user", err)
+	}
+
+	user := &entity.User{
+		Password: hashedPassword,
+		IsActive: true,
+	}
+	switch identifierType {
+	case utils.IdentifierTypeEmail:
+		user.Email = &req.Identifier
+	case utils.IdentifierTypePhone:
+		user.Phone = &req.Identifier
+	case utils.IdentifierTypeUsername:
+		user.Username = &req.Identifier
+	}
+
+	// The user and its first refresh token are stored together, so a failure leaves no
+	// account behind that the client was never told about.
+	var accessToken, refreshToken string
+	err = s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		created, err := s.authRepository.CreateUser(ctx, user)
+		if err != nil {
+			return err
+		}
+		user = created
+
+		if s.requiresVerificationToLogin(user) {
+			return nil
+		}
+
+		accessToken, refreshToken, err = s.issueTokenPair(ctx, user.ID, uuid.New(), nil)
+		return err
+	})
+	if err != nil {
+		return nil, asAppError(err, "failed to register user")
+	}
+
+	if s.requiresVerificationToLogin(user) {
+		return &dto.RegisterResponse{VerificationRequired: true}, nil
+	}
+
+	return &dto.RegisterResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+func (s *authService) Login(ctx context.Context, req *dto.LoginRequest) (*dto.LoginResponse, error) {
+	if err := s.checkLoginBlocked(ctx, req.Identifier, req.ClientIP); err != nil {
+		return nil, err
+	}
+
+	var user *entity.User
+	identifierType := utils.DetectIdentifierType(req.Identifier)
+	if identifierType != utils.IdentifierTypeUnknown {
+		var err error
+		user, err = s.authRepository.GetUserByIdentifier(ctx, req.Identifier, identifierType)
+		if err != nil {
+			return nil, apperrors.NewAppError(apperrors.ErrInternalServer, "failed to login", err)
+		}
+	}
+
+	if user != nil && user.LockedUntil != nil && user.LockedUntil.After(time.Now()) {
+		return nil, newRetryAfterError(apperrors.ErrAccountLocked, "account is temporarily locked, please try again later", time.Until(*user.LockedUntil))
+	}
+
+	if user == nil || !utils.ComparePassword(user.Password, req.Password) {
+		if err := s.recordFailedLogin(ctx, req.Identifier, req.ClientIP, user); err != nil {
+			return nil, apperrors.NewAppError(apperrors.ErrInternalServer, "failed to login", err)
+		}
+		return nil, apperrors.NewAppError(apperrors.ErrInvalidCredentials, "invalid identifier or password", nil)
+	}
+	if !user.IsActive {
+		return nil, apperrors.NewAppError(apperrors.ErrUserInactive, "user is inactive", nil)
+	}
+	if s.requiresVerificationToLogin(user) {
+		return nil, apperrors.NewAppError(apperrors.ErrAccountNotVerified, "please verify your email or phone before logging in", nil)
+	}
+
+	if err := s.clearLoginAttempts(ctx, &req.Identifier); err != nil {
+		return nil, apperrors.NewAppError(apperrors.ErrInternalServer, "failed to login", err)
+	}
+	if user.LockedUntil != nil {
+		if err := s.authRepository.UpdateUserLockedUntil(ctx, user.ID, nil); err != nil {
+			return nil, apperrors.NewAppError(apperrors.ErrInternalServer, "failed to login", err)
+		}
+	}
+
+	accessToken, refreshToken, err := s.issueTokenPair(ctx, user.ID, uuid.New(), nil)
+	if err != nil {
+		return nil, apperrors.NewAppError(apperrors.ErrInternalServer, "failed to login", err)
+	}
+
+	return &dto.LoginResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+func (s *authService) RefreshToken(ctx context.Context, req *dto.RefreshTokenRequest) (*dto.RefreshTokenResponse, error) {
+	claims, err := s.parseRefreshToken(ctx, req.RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	stored, err := s.getActiveRefreshToken(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.authRepository.GetUserByID(ctx, stored.UserID)
+	if err != nil {
+		return nil, apperrors.NewAppError(apperrors.ErrInternalServer, "failed to refresh token", err)
+	}
+	if user == nil {
+		return nil, apperrors.NewAppError(apperrors.ErrInvalidToken, "invalid refresh token", nil)
+	}
+	if !user.IsActive {
+		return nil, apperrors.NewAppError(apperrors.ErrUserInactive, "user is inactive", nil)
+	}
+
+	accessToken, refreshToken, err := s.issueTokenPair(ctx, user.ID, stored.FamilyID, stored)
+	if err != nil {
+		// Another request rotated the same token first
+		if errors.Is(err, errRefreshTokenReused) {
+			return nil, s.handleRefreshTokenReuse(ctx, stored)
+		}
+		return nil, apperrors.NewAppError(apperrors.ErrInternalServer, "failed to refresh token", err)
+	}
+
+	return &dto.RefreshTokenResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+func (s *authService) Logout(ctx context.Context, req *dto.LogoutRequest) error {
+	claims, err := s.parseRefreshToken(ctx, req.RefreshToken)
+	if err != nil {
+		return err
+	}
+
+	stored, err := s.getActiveRefreshToken(ctx, claims)
+	if err != nil {
+		return err
+	}
+
+	if err := s.authRepository.RevokeRefreshTokenFamily(ctx, stored.FamilyID); err != nil {
+		return apperrors.NewAppError(apperrors.ErrInternalServer, "failed to logout", err)
+	}
+
+	if err := s.tokenService.RevokeToken(ctx, claims); err != nil {
+		return apperrors.NewAppError(apperrors.ErrInternalServer, "failed to logout", err)
+	}
+
+	// The access token is optional; it only needs revoking when it still belongs to the same user
+	if req.AccessToken != "" {
+		accessClaims, err := s.tokenService.VerifyToken(ctx, req.AccessToken, constants.ScopeTokenAccess)
+		if err == nil && accessClaims.Subject == claims.Subject {
+			if err := s.tokenService.RevokeToken(ctx, accessClaims); err != nil {
+				return apperrors.NewAppError(apperrors.ErrInternalServer, "failed to logout", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *authService) LogoutAll(ctx context.Context, req *dto.LogoutRequest) error {
+	claims, err := s.parseRefreshToken(ctx, req.RefreshToken)
+	if err != nil {
+		return err
+	}
+
+	stored, err := s.getActiveRefreshToken(ctx, claims)
+	if err != nil {
+		return err
+	}
+
+	return s.RevokeAllSessions(ctx, stored.UserID)
+}
+
+func (s *authService) RevokeAllSessions(ctx context.Context, userID uuid.UUID) error {
+	user, err := s.authRepository.GetUserByID(ctx, userID)
+	if err != nil {
+		return apperrors.NewAppError(apperrors.ErrInternalServer, "failed to revoke sessions", err)
+	}
+	if user == nil {
+		return apperrors.NewAppError(apperrors.ErrNotFound, "user not found", nil)
+	}
+
+	if err := s.authRepository.RevokeUserRefreshTokens(ctx, userID); err != nil {
+		return apperrors.NewAppError(apperrors.ErrInternalServer, "failed to revoke sessions", err)
+	}
+
+	if err := s.tokenService.RevokeAllUserTokens(ctx, userID.String()); err != nil {
+		return apperrors.NewAppError(apperrors.ErrInternalServer, "failed to revoke sessions", err)
+	}
+
+	s.logger.Info().Ctx(ctx).Str("user_id", userID.String()).Msg("revoked all user sessions")
+	return nil
+}
+
+// getActiveUser loads a user that is allowed to act on their account.
+func (s *authService) getActiveUser(ctx context.Context, userID uuid.UUID) (*entity.User, error) {
+	user, err := s.authRepository.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, apperrors.NewAppError(apperrors.ErrInternalServer, "failed to get user", err)
+	}
+	if user == nil {
+		return nil, apperrors.NewAppError(apperrors.ErrUnauthorized, "user not found", nil)
+	}
+	if !user.IsActive {
+		return nil, apperrors.NewAppError(apperrors.ErrUserInactive, "user is inactive", nil)
+	}
+
+	return user, nil
+}
+
+// issueTokenPair signs a new access token and refresh token for the user and records the
+// refresh token in the given family. When previous is set it is rotated to the new refresh
+// token first; errRefreshTokenReused is returned if it had already been rotated.
+func (s *authService) issueTokenPair(ctx context.Context, userID uuid.UUID, familyID uuid.UUID, previous *entity.RefreshToken) (string, string, error) {
+	accessToken, _, err := s.tokenService.GenerateToken(userID.String(), constants.ScopeTokenAccess)
+	if err != nil {
+		return "", "", err
+	}
+
+	refreshToken, refreshClaims, err := s.tokenService.GenerateToken(userID.String(), constants.ScopeTokenRefresh)
+	if err != nil {
+		return "", "", err
+	}
+
+	refreshTokenID, err := uuid.Parse(refreshClaims.ID)
+	if err != nil {
+		return "", "", err
+	}
+
+	err = s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		if previous != nil {
+			rotated, err := s.authRepository.RotateRefreshToken(ctx, previous.ID, refreshTokenID)
+			if err != nil {
+				return err
+			}
+			if !rotated {
+				return errRefreshTokenReused
+			}
+		}
+
+		return s.authRepository.CreateRefreshToken(ctx, &entity.RefreshToken{
+			ID:        refreshTokenID,
+			FamilyID:  familyID,
+			UserID:    userID,
+			ExpiresAt: refreshClaims.ExpiresAt.Time,
+		})
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	return accessToken, refreshToken, nil
+}
+
+// getActiveRefreshToken loads the stored record of a verified refresh token.
+// Presenting a token that was already rotated is treated as token theft and revokes its whole family.
+func (s *authService) getActiveRefreshToken(ctx context.Context, claims *utils.TokenClaims) (*entity.RefreshToken, error) {
+	tokenID, err := uuid.Parse(claims.ID)
+	if err != nil {
+		return nil, apperrors.NewAppError(apperrors.ErrInvalidToken, "invalid refresh token", err)
+	}
+
+	stored, err := s.authRepository.GetRefreshTokenByID(ctx, tokenID)
+	if err != nil {
+		return nil, apperrors.NewAppError(apperrors.ErrInternalServer, "failed to validate refresh token", err)
+	}
+	if stored == nil || stored.UserID.String() != claims.Subject {
+		return nil, apperrors.NewAppError(apperrors.ErrInvalidToken, "invalid refresh token", nil)
+	}
+
+	if stored.RevokedAt != nil {
+		if stored.ReplacedBy != nil {
+			return nil, s.handleRefreshTokenReuse(ctx, stored)
+		}
+		return nil, apperrors.NewAppError(apperrors.ErrInvalidToken, "refresh token has been revoked", nil)
+	}
+
+	return stored, nil
+}
+
+// handleRefreshTokenReuse revokes the family of a refresh token that was replayed after rotation.
+func (s *authService) handleRefreshTokenReuse(ctx context.Context, token *entity.RefreshToken) error {
+	s.logger.Warn().
+		Ctx(ctx).
+		Str("user_id", token.UserID.String()).
+		Str("family_id", token.FamilyID.String()).
+		Str("token_id", token.ID.String()).
+		Msg("refresh token reuse detected, revoking token family")
+
+	if err := s.authRepository.RevokeRefreshTokenFamily(ctx, token.FamilyID); err != nil {
+		return apperrors.NewAppError(apperrors.ErrInternalServer, "failed to refresh token", err)
+	}
+
+	return apperrors.NewAppError(apperrors.ErrRefreshTokenReused, "refresh token has already been used, please login again", nil)
+}
+
+// parseRefreshToken validates a refresh token, including its revocation status.
+func (s *authService) parseRefreshToken(ctx context.Context, token string) (*utils.TokenClaims, error) {
+	claims, err := s.tokenService.VerifyToken(ctx, token, constants.ScopeTokenRefresh)
+	if err != nil {
+		switch {
+		case errors.Is(err, utils.ErrExpiredToken):
+			return nil, apperrors.NewAppError(apperrors.ErrTokenExpired, "refresh token has expired", err)
+		case errors.Is(err, utils.ErrRevokedToken):
+			return nil, apperrors.NewAppError(apperrors.ErrInvalidToken, "refresh token has been revoked", err)
+		case errors.Is(err, utils.ErrInvalidToken), errors.Is(err, utils.ErrInvalidTokenScope):
+			return nil, apperrors.NewAppError(apperrors.ErrInvalidToken, "invalid refresh token", err)
+		default:
+			return nil, apperrors.NewAppError(apperrors.ErrInternalServer, "failed to validate refresh token", err)
+		}
+	}
+
+	return claims, nil
+}
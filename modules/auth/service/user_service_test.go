@@ -0,0 +1,289 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go-api-starter/modules/auth/dto"
+	"go-api-starter/modules/auth/entity"
+	"go-api-starter/pkg/apperrors"
+	"go-api-starter/pkg/config"
+	"go-api-starter/pkg/constants"
+	"go-api-starter/pkg/utils"
+
+	"github.com/google/uuid"
+)
+
+const testPassword = "Str0ng!Password"
+
+func assertErrorCode(t *testing.T, err error, code apperrors.ErrorCode) *apperrors.AppError {
+	t.Helper()
+
+	var appErr *apperrors.AppError
+	if !errors.As(err, &appErr) {
+		t.Fatalf("error = %v, want app error with code %d", err, code)
+	}
+	if appErr.Code != code {
+		t.Fatalf("error code = %d (%s), want %d", appErr.Code, appErr.Message, code)
+	}
+	return appErr
+}
+
+// registerTestUser registers identifier with testPassword and returns the stored user.
+func registerTestUser(t *testing.T, service *authService, repo *fakeAuthRepository, identifier string) *entity.User {
+	t.Helper()
+
+	ctx := context.Background()
+	if _, err := service.Register(ctx, &dto.RegisterRequest{Identifier: identifier, Password: testPassword}); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	user, _ := repo.GetUserByIdentifier(ctx, identifier, utils.DetectIdentifierType(identifier))
+	if user == nil {
+		t.Fatalf("user %q was not stored", identifier)
+	}
+	return user
+}
+
+func TestAuthServiceRegister(t *testing.T) {
+	ctx := context.Background()
+	service, repo, _ := newTestAuthService(t, nil)
+
+	response, err := service.Register(ctx, &dto.RegisterRequest{Identifier: "alice@example.com", Password: testPassword})
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if response.AccessToken == "" || response.RefreshToken == "" || response.VerificationRequired {
+		t.Fatalf("Register response = %+v, want a token pair", response)
+	}
+
+	user, _ := repo.GetUserByIdentifier(ctx, "alice@example.com", utils.IdentifierTypeEmail)
+	if user == nil {
+		t.Fatal("user was not stored")
+	}
+	if user.Password == testPassword || !utils.ComparePassword(user.Password, testPassword) {
+		t.Fatal("stored password is not a hash of the given password")
+	}
+	if !user.IsActive {
+		t.Fatal("registered user is not active")
+	}
+	if got := repo.activeRefreshTokens(user.ID); got != 1 {
+		t.Fatalf("active refresh tokens = %d, want 1", got)
+	}
+
+	claims, err := service.tokenService.VerifyToken(ctx, response.AccessToken, constants.ScopeTokenAccess)
+	if err != nil {
+		t.Fatalf("VerifyToken(access): %v", err)
+	}
+	if claims.Subject != user.ID.String() {
+		t.Fatalf("access token subject = %q, want %q", claims.Subject, user.ID)
+	}
+
+	_, err = service.Register(ctx, &dto.RegisterRequest{Identifier: "alice@example.com", Password: testPassword})
+	assertErrorCode(t, err, apperrors.ErrAlreadyExists)
+}
+
+func TestAuthServiceRegisterClosed(t *testing.T) {
+	service, _, _ := newTestAuthService(t, func(cfg *config.Config) {
+		cfg.Features = map[string]bool{constants.FeatureDisableRegistration: true}
+	})
+
+	_, err := service.Register(context.Background(), &dto.RegisterRequest{Identifier: "alice@example.com", Password: testPassword})
+	assertErrorCode(t, err, apperrors.ErrForbidden)
+}
+
+func TestAuthServiceRegisterVerificationPolicy(t *testing.T) {
+	ctx := context.Background()
+	service, repo, _ := newTestAuthService(t, func(cfg *config.Config) {
+		cfg.Auth.VerificationPolicy = constants.VerificationPolicyLogin
+	})
+
+	response, err := service.Register(ctx, &dto.RegisterRequest{Identifier: "alice@example.com", Password: testPassword})
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if !response.VerificationRequired || response.AccessToken != "" || response.RefreshToken != "" {
+		t.Fatalf("Register response = %+v, want verification required without tokens", response)
+	}
+
+	user, _ := repo.GetUserByIdentifier(ctx, "alice@example.com", utils.IdentifierTypeEmail)
+	if got := repo.activeRefreshTokens(user.ID); got != 0 {
+		t.Fatalf("active refresh tokens = %d, want 0", got)
+	}
+
+	_, err = service.Login(ctx, &dto.LoginRequest{Identifier: "alice@example.com", Password: testPassword})
+	assertErrorCode(t, err, apperrors.ErrAccountNotVerified)
+
+	_, err = service.Register(ctx, &dto.RegisterRequest{Identifier: "alice", Password: testPassword})
+	assertErrorCode(t, err, apperrors.ErrInvalidInput)
+}
+
+func TestAuthServiceLogin(t *testing.T) {
+	ctx := context.Background()
+	service, repo, _ := newTestAuthService(t, nil)
+	user := registerTestUser(t, service, repo, "alice@example.com")
+
+	response, err := service.Login(ctx, &dto.LoginRequest{Identifier: "alice@example.com", Password: testPassword, ClientIP: "192.0.2.1"})
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if response.AccessToken == "" || response.RefreshToken == "" {
+		t.Fatalf("Login response = %+v, want a token pair", response)
+	}
+	// One session from Register, one from Login
+	if got := repo.activeRefreshTokens(user.ID); got != 2 {
+		t.Fatalf("active refresh tokens = %d, want 2", got)
+	}
+
+	_, err = service.Login(ctx, &dto.LoginRequest{Identifier: "alice@example.com", Password: "wrong-password", ClientIP: "192.0.2.1"})
+	assertErrorCode(t, err, apperrors.ErrInvalidCredentials)
+
+	_, err = service.Login(ctx, &dto.LoginRequest{Identifier: "bob@example.com", Password: testPassword, ClientIP: "192.0.2.1"})
+	assertErrorCode(t, err, apperrors.ErrInvalidCredentials)
+}
+
+func TestAuthServiceLoginLockout(t *testing.T) {
+	ctx := context.Background()
+	service, repo, server := newTestAuthService(t, nil)
+	user := registerTestUser(t, service, repo, "alice@example.com")
+	wrong := &dto.LoginRequest{Identifier: "alice@example.com", Password: "wrong-password", ClientIP: "192.0.2.1"}
+
+	// A success resets the count, so only consecutive failures lock the account
+	for range service.config.Auth.MaxLoginAttempts - 1 {
+		if _, err := service.Login(ctx, wrong); err == nil {
+			t.Fatal("Login with a wrong password succeeded")
+		}
+	}
+	if _, err := service.Login(ctx, &dto.LoginRequest{Identifier: "alice@example.com", Password: testPassword}); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+
+	for range service.config.Auth.MaxLoginAttempts {
+		_, err := service.Login(ctx, wrong)
+		assertErrorCode(t, err, apperrors.ErrInvalidCredentials)
+	}
+
+	locked, _ := repo.GetUserByID(ctx, user.ID)
+	if locked.LockedUntil == nil || !locked.LockedUntil.After(time.Now()) {
+		t.Fatalf("LockedUntil = %v, want a time in the future", locked.LockedUntil)
+	}
+
+	_, err := service.Login(ctx, &dto.LoginRequest{Identifier: "alice@example.com", Password: testPassword})
+	appErr := assertErrorCode(t, err, apperrors.ErrAccountLocked)
+	details, ok := appErr.Details.(dto.LoginBlockedDetails)
+	if !ok || details.RetryAfter <= 0 {
+		t.Fatalf("details = %#v, want a positive retry-after", appErr.Details)
+	}
+
+	// Once the counter expires the lock in Postgres still holds until LockedUntil
+	server.FastForward(time.Duration(service.config.Auth.BlockDuration) * time.Second)
+	_, err = service.Login(ctx, &dto.LoginRequest{Identifier: "alice@example.com", Password: testPassword})
+	assertErrorCode(t, err, apperrors.ErrAccountLocked)
+
+	if err := service.UnlockUser(ctx, user.ID); err != nil {
+		t.Fatalf("UnlockUser: %v", err)
+	}
+	if _, err := service.Login(ctx, &dto.LoginRequest{Identifier: "alice@example.com", Password: testPassword}); err != nil {
+		t.Fatalf("Login after unlock: %v", err)
+	}
+}
+
+func TestAuthServiceRefreshTokenRotation(t *testing.T) {
+	ctx := context.Background()
+	service, repo, _ := newTestAuthService(t, nil)
+	user := registerTestUser(t, service, repo, "alice@example.com")
+
+	login, err := service.Login(ctx, &dto.LoginRequest{Identifier: "alice@example.com", Password: testPassword})
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+
+	rotated, err := service.RefreshToken(ctx, &dto.RefreshTokenRequest{RefreshToken: login.RefreshToken})
+	if err != nil {
+		t.Fatalf("RefreshToken: %v", err)
+	}
+	if rotated.RefreshToken == login.RefreshToken {
+		t.Fatal("RefreshToken returned the presented token")
+	}
+
+	oldClaims, _ := service.tokenService.VerifyToken(ctx, login.RefreshToken, constants.ScopeTokenRefresh)
+	newClaims, _ := service.tokenService.VerifyToken(ctx, rotated.RefreshToken, constants.ScopeTokenRefresh)
+	oldStored, _ := repo.GetRefreshTokenByID(ctx, uuid.MustParse(oldClaims.ID))
+	newStored, _ := repo.GetRefreshTokenByID(ctx, uuid.MustParse(newClaims.ID))
+	if oldStored.RevokedAt == nil || oldStored.ReplacedBy == nil || *oldStored.ReplacedBy != newStored.ID {
+		t.Fatalf("rotated token = %+v, want revoked and replaced by %s", oldStored, newStored.ID)
+	}
+	if newStored.FamilyID != oldStored.FamilyID {
+		t.Fatal("rotated token left its family")
+	}
+
+	// Replaying the rotated token revokes the whole family, including the token it was replaced by
+	_, err = service.RefreshToken(ctx, &dto.RefreshTokenRequest{RefreshToken: login.RefreshToken})
+	assertErrorCode(t, err, apperrors.ErrRefreshTokenReused)
+
+	_, err = service.RefreshToken(ctx, &dto.RefreshTokenRequest{RefreshToken: rotated.RefreshToken})
+	assertErrorCode(t, err, apperrors.ErrInvalidToken)
+
+	// The session from Register is a different family and survives
+	if got := repo.activeRefreshTokens(user.ID); got != 1 {
+		t.Fatalf("active refresh tokens = %d, want 1", got)
+	}
+
+	_, err = service.RefreshToken(ctx, &dto.RefreshTokenRequest{RefreshToken: login.AccessToken})
+	assertErrorCode(t, err, apperrors.ErrInvalidToken)
+}
+
+func TestAuthServiceLogout(t *testing.T) {
+	ctx := context.Background()
+	service, repo, _ := newTestAuthService(t, nil)
+	user := registerTestUser(t, service, repo, "alice@example.com")
+
+	login, err := service.Login(ctx, &dto.LoginRequest{Identifier: "alice@example.com", Password: testPassword})
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+
+	if err := service.Logout(ctx, &dto.LogoutRequest{RefreshToken: login.RefreshToken, AccessToken: login.AccessToken}); err != nil {
+		t.Fatalf("Logout: %v", err)
+	}
+
+	if _, err := service.tokenService.VerifyToken(ctx, login.AccessToken, constants.ScopeTokenAccess); !errors.Is(err, utils.ErrRevokedToken) {
+		t.Fatalf("VerifyToken(access) error = %v, want %v", err, utils.ErrRevokedToken)
+	}
+
+	_, err = service.RefreshToken(ctx, &dto.RefreshTokenRequest{RefreshToken: login.RefreshToken})
+	assertErrorCode(t, err, apperrors.ErrInvalidToken)
+
+	err = service.Logout(ctx, &dto.LogoutRequest{RefreshToken: login.RefreshToken})
+	assertErrorCode(t, err, apperrors.ErrInvalidToken)
+
+	// Only the logged out session ends
+	if got := repo.activeRefreshTokens(user.ID); got != 1 {
+		t.Fatalf("active refresh tokens = %d, want 1", got)
+	}
+}
+
+func TestAuthServiceLogoutAll(t *testing.T) {
+	ctx := context.Background()
+	service, repo, _ := newTestAuthService(t, nil)
+	user := registerTestUser(t, service, repo, "alice@example.com")
+
+	login, err := service.Login(ctx, &dto.LoginRequest{Identifier: "alice@example.com", Password: testPassword})
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	// Revoke-all covers tokens issued before the revocation millisecond
+	time.Sleep(2 * time.Millisecond)
+
+	if err := service.LogoutAll(ctx, &dto.LogoutRequest{RefreshToken: login.RefreshToken}); err != nil {
+		t.Fatalf("LogoutAll: %v", err)
+	}
+
+	if got := repo.activeRefreshTokens(user.ID); got != 0 {
+		t.Fatalf("active refresh tokens = %d, want 0", got)
+	}
+	if _, err := service.tokenService.VerifyToken(ctx, login.AccessToken, constants.ScopeTokenAccess); !errors.Is(err, utils.ErrRevokedToken) {
+		t.Fatalf("VerifyToken(access) error = %v, want %v", err, utils.ErrRevokedToken)
+	}
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"go-api-starter/modules/auth/dto"
+	"go-api-starter/modules/auth/entity"
+	"go-api-starter/pkg/apperrors"
+	"go-api-starter/pkg/constants"
+	"go-api-starter/pkg/utils"
+
+	"github.com/google/uuid"
+)
+
+// RequestEmailVerification mails a verification link to an unverified email address.
+// Unknown or already verified addresses are ignored silently so the endpoint cannot enumerate accounts.
+func (s *authService) RequestEmailVerification(ctx context.Context, req *dto.RequestEmailVerificationRequest) error {
+	if err := s.limitOTPRequest(ctx, req.Email, req.ClientIP); err != nil {
+		return err
+	}
+
+	user, err := s.authRepository.GetUserByIdentifier(ctx, req.Email, utils.IdentifierTypeEmail)
+	if err != nil {
+		return apperrors.NewAppError(apperrors.ErrInternalServer, "failed to request email verification", err)
+	}
+	if user == nil || user.EmailVerifiedAt != nil {
+		return nil
+	}
+
+	token, _, err := s.tokenService.GenerateBoundToken(user.ID.String(), constants.ScopeTokenEmailVerification, emailBinding(*user.Email))
+	if err != nil {
+		return apperrors.NewAppError(apperrors.ErrInternalServer, "failed to request email verification", err)
+	}
+
+	s.deliverInBackground(ctx, func(context.Context) error {
+		return utils.SendVerificationEmail(s.emailConfig, *user.Email, token, s.config.Auth.EmailVerificationURL)
+	})
+
+	return nil
+}
+
+func (s *authService) ConfirmEmailVerification(ctx context.Context, req *dto.ConfirmEmailVerificationRequest) error {
+	claims, err := s.tokenService.VerifyToken(ctx, req.Token, constants.ScopeTokenEmailVerification)
+	if err != nil {
+		switch {
+		case errors.Is(err, utils.ErrExpiredToken):
+			return apperrors.NewAppError(apperrors.ErrTokenExpired, "email verification token has expired", err)
+		case errors.Is(err, utils.ErrInvalidToken), errors.Is(err, utils.ErrInvalidTokenScope), errors.Is(err, utils.ErrRevokedToken):
+			return apperrors.NewAppError(apperrors.ErrInvalidToken, "invalid email verification token", err)
+		default:
+			return apperrors.NewAppError(apperrors.ErrInternalServer, "failed to verify email", err)
+		}
+	}
+
+	userID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return apperrors.NewAppError(apperrors.ErrInvalidToken, "invalid email verification token", err)
+	}
+
+	user, err := s.authRepository.GetUserByID(ctx, userID)
+	if err != nil {
+		return apperrors.NewAppError(apperrors.ErrInternalServer, "failed to verify email", err)
+	}
+	// A link sent before the address was changed must not verify the new one
+	if user == nil || user.Email == nil || claims.Binding != emailBinding(*user.Email) {
+		return apperrors.NewAppError(apperrors.ErrInvalidToken, "invalid email verification token", nil)
+	}
+	if user.EmailVerifiedAt != nil {
+		return apperrors.NewAppError(apperrors.ErrAlreadyVerified, "email is already verified", nil)
+	}
+
+	if err := s.authRepository.MarkEmailVerified(ctx, user.ID); err != nil {
+		return apperrors.NewAppError(apperrors.ErrInternalServer, "failed to verify email", err)
+	}
+
+	// Verification links are single use
+	if err := s.tokenService.RevokeToken(ctx, claims); err != nil {
+		return apperrors.NewAppError(apperrors.ErrInternalServer, "failed to verify email", err)
+	}
+
+	return nil
+}
+
+// emailBinding is the binding claim of email verification tokens. The address is hashed
+// because the token payload is only signed, not encrypted.
+func emailBinding(email string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(email)))
+	return hex.EncodeToString(sum[:])
+}
+
+// RequestPhoneVerification texts an OTP to an unverified phone number.
+// Unknown or already verified numbers are ignored silently so the endpoint cannot enumerate accounts.
+func (s *authService) RequestPhoneVerification(ctx context.Context, req *dto.RequestPhoneVerificationRequest) error {
+	if err := s.limitOTPRequest(ctx, req.Phone, req.ClientIP); err != nil {
+		return err
+	}
+
+	user, err := s.authRepository.GetUserByIdentifier(ctx, req.Phone, utils.IdentifierTypePhone)
+	if err != nil {
+		return apperrors.NewAppError(apperrors.ErrInternalServer, "failed to request phone verification", err)
+	}
+	if user == nil || user.PhoneVerifiedAt != nil {
+		return nil
+	}
+
+	// Codes are keyed by the number so confirming one does not need the account first
+	otp, err := s.otpService.GenerateAndStoreOTP(ctx, req.Phone, utils.OTPTypeVerification)
+	if err != nil {
+		return apperrors.NewAppError(apperrors.ErrInternalServer, "failed to request phone verification", err)
+	}
+
+	s.deliverInBackground(ctx, func(ctx context.Context) error {
+		return s.sendOTPBySMS(ctx, *user.Phone, "Phone verification code", otp.Code)
+	})
+
+	return nil
+}
+
+// ConfirmPhoneVerification checks the code before looking the number up, so wrong codes
+// are answered the same way whether or not the number belongs to an account.
+func (s *authService) ConfirmPhoneVerification(ctx context.Context, req *dto.ConfirmPhoneVerificationRequest) error {
+	if err := s.limitOTPVerification(ctx, req.ClientIP); err != nil {
+		return err
+	}
+
+	valid, err := s.otpService.VerifyOTP(ctx, req.Phone, req.OTP, utils.OTPTypeVerification)
+	if err != nil || !valid {
+		return otpAppError(err, "failed to verify phone")
+	}
+
+	user, err := s.authRepository.GetUserByIdentifier(ctx, req.Phone, utils.IdentifierTypePhone)
+	if err != nil {
+		return apperrors.NewAppError(apperrors.ErrInternalServer, "failed to verify phone", err)
+	}
+	if user == nil {
+		return apperrors.NewAppError(apperrors.ErrInvalidOTP, "invalid or expired OTP", nil)
+	}
+	if user.PhoneVerifiedAt != nil {
+		return apperrors.NewAppError(apperrors.ErrAlreadyVerified, "phone is already verified", nil)
+	}
+
+	if err := s.authRepository.MarkPhoneVerified(ctx, user.ID); err != nil {
+		return apperrors.NewAppError(apperrors.ErrInternalServer, "failed to verify phone", err)
+	}
+
+	return nil
+}
+
+// EnsureVerified rejects users without a verified email or phone when a verification policy is enabled.
+func (s *authService) EnsureVerified(ctx context.Context, userID uuid.UUID) error {
+	if !s.verificationRequired() {
+		return nil
+	}
+
+	user, err := s.getActiveUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !isVerified(user) {
+		return apperrors.NewAppError(apperrors.ErrAccountNotVerified, "please verify your email or phone first", nil)
+	}
+
+	return nil
+}
+
+// verificationRequired reports whether a verification policy is enabled.
+func (s *authService) verificationRequired() bool {
+	policy := s.config.Auth.VerificationPolicy
+	return policy != "" && policy != constants.VerificationPolicyNone
+}
+
+// requiresVerificationToLogin reports whether the login policy keeps this user from getting tokens.
+func (s *authService) requiresVerificationToLogin(user *entity.User) bool {
+	return s.config.Auth.VerificationPolicy == constants.VerificationPolicyLogin && !isVerified(user)
+}
+
+func isVerified(user *entity.User) bool {
+	return user.EmailVerifiedAt != nil || user.PhoneVerifiedAt != nil
+}
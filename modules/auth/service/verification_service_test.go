@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"go-api-starter/modules/auth/dto"
+	"go-api-starter/pkg/apperrors"
+	"go-api-starter/pkg/constants"
+)
+
+func TestAuthServiceConfirmEmailVerification(t *testing.T) {
+	ctx := context.Background()
+	service, repo, _ := newTestAuthService(t, nil)
+	user := registerTestUser(t, service, repo, "alice@example.com")
+
+	token, _, err := service.tokenService.GenerateBoundToken(user.ID.String(), constants.ScopeTokenEmailVerification, emailBinding("Alice@Example.com"))
+	if err != nil {
+		t.Fatalf("GenerateBoundToken: %v", err)
+	}
+
+	if err := service.ConfirmEmailVerification(ctx, &dto.ConfirmEmailVerificationRequest{Token: token}); err != nil {
+		t.Fatalf("ConfirmEmailVerification: %v", err)
+	}
+	verified, _ := repo.GetUserByID(ctx, user.ID)
+	if verified.EmailVerifiedAt == nil {
+		t.Fatal("email was not marked verified")
+	}
+
+	// Verification links are single use
+	err = service.ConfirmEmailVerification(ctx, &dto.ConfirmEmailVerificationRequest{Token: token})
+	assertErrorCode(t, err, apperrors.ErrInvalidToken)
+}
+
+func TestAuthServiceConfirmEmailVerificationBinding(t *testing.T) {
+	ctx := context.Background()
+	service, repo, _ := newTestAuthService(t, nil)
+	user := registerTestUser(t, service, repo, "alice@example.com")
+
+	oldToken, _, err := service.tokenService.GenerateBoundToken(user.ID.String(), constants.ScopeTokenEmailVerification, emailBinding("alice@example.com"))
+	if err != nil {
+		t.Fatalf("GenerateBoundToken: %v", err)
+	}
+	unboundToken, _, err := service.tokenService.GenerateToken(user.ID.String(), constants.ScopeTokenEmailVerification)
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+
+	// The address changes after the link was sent
+	newEmail := "mallory@example.com"
+	repo.mu.Lock()
+	changed := repo.users[user.ID]
+	changed.Email = &newEmail
+	repo.users[user.ID] = changed
+	repo.mu.Unlock()
+
+	for name, token := range map[string]string{"old address": oldToken, "unbound": unboundToken} {
+		t.Run(name, func(t *testing.T) {
+			err := service.ConfirmEmailVerification(ctx, &dto.ConfirmEmailVerificationRequest{Token: token})
+			assertErrorCode(t, err, apperrors.ErrInvalidToken)
+		})
+	}
+
+	stored, _ := repo.GetUserByID(ctx, user.ID)
+	if stored.EmailVerifiedAt != nil {
+		t.Fatal("the new address was verified by a link sent to another one")
+	}
+}
@@ -1 +1,267 @@
 package validator
+
+import (
+	"regexp"
+
+	"go-api-starter/modules/auth/dto"
+	"go-api-starter/pkg/utils"
+
+	"github.com/google/uuid"
+)
+
+func ValidateRegisterRequest(req *dto.RegisterRequest) *utils.ValidationResult {
+	result := utils.NewValidationResult()
+
+	req.Identifier = utils.TrimSpace(req.Identifier)
+	if utils.IsEmpty(req.Identifier) {
+		result.AddError("identifier", "identifier is required")
+	} else if utils.DetectIdentifierType(req.Identifier) == utils.IdentifierTypeUnknown {
+		result.AddError("identifier", "identifier must be a valid email, phone number or username")
+	}
+
+	if err := utils.ValidateStrongPassword(req.Password); err != nil {
+		result.AddError("password", err.Error())
+	}
+
+	return result
+}
+
+func ValidateLoginRequest(req *dto.LoginRequest) *utils.ValidationResult {
+	result := utils.NewValidationResult()
+
+	req.Identifier = utils.TrimSpace(req.Identifier)
+	if utils.IsEmpty(req.Identifier) {
+		result.AddError("identifier", "identifier is required")
+	}
+
+	if req.Password == "" {
+		result.AddError("password", "password is required")
+	}
+
+	return result
+}
+
+func ValidateRefreshTokenRequest(req *dto.RefreshTokenRequest) *utils.ValidationResult {
+	result := utils.NewValidationResult()
+
+	if utils.IsEmpty(req.RefreshToken) {
+		result.AddError("refresh_token", "refresh token is required")
+	}
+
+	return result
+}
+
+func ValidateLogoutRequest(req *dto.LogoutRequest) *utils.ValidationResult {
+	result := utils.NewValidationResult()
+
+	if utils.IsEmpty(req.RefreshToken) {
+		result.AddError("refresh_token", "refresh token is required")
+	}
+
+	return result
+}
+
+func ValidateForgotPasswordRequest(req *dto.ForgotPasswordRequest) *utils.ValidationResult {
+	result := utils.NewValidationResult()
+
+	req.Identifier = utils.TrimSpace(req.Identifier)
+	if utils.IsEmpty(req.Identifier) {
+		result.AddError("identifier", "identifier is required")
+	}
+
+	return result
+}
+
+func ValidateVerifyOTPRequest(req *dto.VerifyOTPRequest) *utils.ValidationResult {
+	result := utils.NewValidationResult()
+
+	req.Identifier = utils.TrimSpace(req.Identifier)
+	if utils.IsEmpty(req.Identifier) {
+		result.AddError("identifier", "identifier is required")
+	}
+
+	if utils.IsEmpty(req.ChallengeID) {
+		result.AddError("challenge_id", "challenge id is required")
+	}
+
+	if utils.IsEmpty(req.OTP) {
+		result.AddError("otp", "otp is required")
+	}
+
+	return result
+}
+
+func ValidateResetPasswordRequest(req *dto.ResetPasswordRequest) *utils.ValidationResult {
+	result := utils.NewValidationResult()
+
+	if utils.IsEmpty(req.Token) {
+		result.AddError("token", "token is required")
+	}
+
+	if err := utils.ValidateStrongPassword(req.NewPassword); err != nil {
+		result.AddError("new_password", err.Error())
+	}
+
+	if req.ConfirmPassword != req.NewPassword {
+		result.AddError("confirm_password", "confirm password does not match")
+	}
+
+	return result
+}
+
+func ValidateChangePasswordRequest(req *dto.ChangePasswordRequest) *utils.ValidationResult {
+	result := utils.NewValidationResult()
+
+	if req.Password == "" {
+		result.AddError("password", "current password is required")
+	}
+
+	if err := utils.ValidateStrongPassword(req.NewPassword); err != nil {
+		result.AddError("new_password", err.Error())
+	}
+
+	if req.ConfirmPassword != req.NewPassword {
+		result.AddError("confirm_password", "confirm password does not match")
+	}
+
+	if utils.IsEmpty(req.OTP) {
+		result.AddError("otp", "otp is required")
+	}
+
+	return result
+}
+
+func ValidateRequestEmailVerificationRequest(req *dto.RequestEmailVerificationRequest) *utils.ValidationResult {
+	result := utils.NewValidationResult()
+
+	req.Email = utils.TrimSpace(req.Email)
+	if !utils.IsValidEmail(req.Email) {
+		result.AddError("email", "email is invalid")
+	}
+
+	return result
+}
+
+func ValidateConfirmEmailVerificationRequest(req *dto.ConfirmEmailVerificationRequest) *utils.ValidationResult {
+	result := utils.NewValidationResult()
+
+	if utils.IsEmpty(req.Token) {
+		result.AddError("token", "token is required")
+	}
+
+	return result
+}
+
+func ValidateRequestPhoneVerificationRequest(req *dto.RequestPhoneVerificationRequest) *utils.ValidationResult {
+	result := utils.NewValidationResult()
+
+	req.Phone = utils.TrimSpace(req.Phone)
+	if !utils.IsValidPhone(req.Phone) {
+		result.AddError("phone", "phone is invalid")
+	}
+
+	return result
+}
+
+func ValidateConfirmPhoneVerificationRequest(req *dto.ConfirmPhoneVerificationRequest) *utils.ValidationResult {
+	result := utils.NewValidationResult()
+
+	req.Phone = utils.TrimSpace(req.Phone)
+	if !utils.IsValidPhone(req.Phone) {
+		result.AddError("phone", "phone is invalid")
+	}
+
+	if utils.IsEmpty(req.OTP) {
+		result.AddError("otp", "otp is required")
+	}
+
+	return result
+}
+
+func ValidateUserRequest(req *dto.UserRequest) *utils.ValidationResult {
+	result := utils.NewValidationResult()
+
+	req.Email = utils.TrimSpace(req.Email)
+	req.Phone = utils.TrimSpace(req.Phone)
+	req.Username = utils.TrimSpacePointer(req.Username)
+
+	if req.Email != "" && !utils.IsValidEmail(req.Email) {
+		result.AddError("email", "email is invalid")
+	}
+
+	if req.Phone != "" && !utils.IsValidPhone(req.Phone) {
+		result.AddError("phone", "phone is invalid")
+	}
+
+	hasUsername := req.Username != nil && *req.Username != ""
+	if hasUsername && !utils.IsUsername(*req.Username) {
+		result.AddError("username", "username must be 3-50 characters and not an email or phone number")
+	}
+
+	if req.Email == "" && req.Phone == "" && !hasUsername {
+		result.AddError("identifier", "at least one of email, phone or username is required")
+	}
+
+	return result
+}
+
+// slugPattern allows lowercase slugs such as "admin" or "users.read".
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+([._:-][a-z0-9]+)*$`)
+
+func validateSlugAndName(result *utils.ValidationResult, slug *string, name *string, description *string) {
+	*slug = utils.TrimSpace(*slug)
+	if utils.IsEmpty(*slug) {
+		result.AddError("slug", "slug is required")
+	} else if len(*slug) > 100 || !slugPattern.MatchString(*slug) {
+		result.AddError("slug", "slug must be at most 100 lowercase letters, digits, '.', '_', ':' or '-'")
+	}
+
+	*name = utils.TrimSpace(*name)
+	if utils.IsEmpty(*name) {
+		result.AddError("name", "name is required")
+	} else if len(*name) > 255 {
+		result.AddError("name", "name must be at most 255 characters")
+	}
+
+	if description != nil {
+		*description = utils.TrimSpace(*description)
+	}
+}
+
+func ValidateRoleRequest(req *dto.RoleRequest) *utils.ValidationResult {
+	result := utils.NewValidationResult()
+	validateSlugAndName(result, &req.Slug, &req.Name, req.Description)
+	return result
+}
+
+func ValidatePermissionRequest(req *dto.PermissionRequest) *utils.ValidationResult {
+	result := utils.NewValidationResult()
+	validateSlugAndName(result, &req.Slug, &req.Name, req.Description)
+	return result
+}
+
+func ValidateSetRolePermissionsRequest(req *dto.SetRolePermissionsRequest) *utils.ValidationResult {
+	result := utils.NewValidationResult()
+
+	if req.PermissionIDs == nil {
+		result.AddError("permission_ids", "permission_ids is required")
+	}
+	for _, id := range req.PermissionIDs {
+		if id == uuid.Nil {
+			result.AddError("permission_ids", "permission_ids must not contain empty ids")
+			break
+		}
+	}
+
+	return result
+}
+
+func ValidateAssignRoleRequest(req *dto.AssignRoleRequest) *utils.ValidationResult {
+	result := utils.NewValidationResult()
+
+	if req.RoleID == uuid.Nil {
+		result.AddError("role_id", "role_id is required")
+	}
+
+	return result
+}
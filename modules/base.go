@@ -2,10 +2,17 @@ package modules
 
 import (
 	"go-api-starter/modules/auth"
+	"go-api-starter/pkg/migrations"
 
 	"github.com/samber/do/v2"
 )
 
 var BasePackage = do.Package(
 	auth.Package,
+	do.Eager(Migrations),
 )
+
+// Migrations lists the migration sources of every module, applied in this order.
+var Migrations = []migrations.Source{
+	auth.Migrations,
+}
@@ -5,6 +5,7 @@ import "fmt"
 type AppError struct {
 	Code    ErrorCode `json:"code"`
 	Message string    `json:"message"`
+	Details any       `json:"details,omitempty"`
 	Err     error     `json:"-"` // Internal error (not exposed to client)
 }
 
@@ -23,3 +24,9 @@ func NewAppError(code ErrorCode, message string, err error) *AppError {
 		Err:     err,
 	}
 }
+
+// WithDetails attaches client-facing details, e.g. a retry-after hint, to the error.
+func (e *AppError) WithDetails(details any) *AppError {
+	e.Details = details
+	return e
+}
@@ -2,20 +2,53 @@ package apperrors
 
 type ErrorCode int
 
+// Codes are sent to clients, so every code has an explicit value that must never change.
+// The first code of the validation, resource, business and system ranges predates the
+// split into per-range blocks and keeps its original wire value (2002, 3003, 4004, 5005).
+
+// Authentication & Authorization errors (1000-1099)
 const (
-	// Authentication & Authorization errors (1000-1099)
-	ErrUnauthorized ErrorCode = 1000 + iota
-	ErrForbidden
+	ErrUnauthorized         ErrorCode = 1000
+	ErrForbidden            ErrorCode = 1001
+	ErrInvalidCredentials   ErrorCode = 1002
+	ErrInvalidToken         ErrorCode = 1003
+	ErrTokenExpired         ErrorCode = 1004
+	ErrUserInactive         ErrorCode = 1005
+	ErrRefreshTokenReused   ErrorCode = 1006
+	ErrAccountLocked        ErrorCode = 1007
+	ErrTooManyLoginAttempts ErrorCode = 1008
+	ErrAccountNotVerified   ErrorCode = 1009
+	ErrInvalidSignature     ErrorCode = 1010
+	ErrTooManyOTPRequests   ErrorCode = 1011
+)
 
-	// Validation errors (2000-2099)
-	ErrInvalidInput ErrorCode = 2000 + iota
+// Validation errors (2000-2099)
+const (
+	ErrInvalidInput      ErrorCode = 2002
+	ErrInvalidOTP        ErrorCode = 2003
+	ErrIncorrectPassword ErrorCode = 2004
+	ErrPasswordReused    ErrorCode = 2005
+)
 
-	// Resource errors (3000-3099)
-	ErrNotFound ErrorCode = 3000 + iota
+// Resource errors (3000-3099)
+const (
+	ErrNotFound                ErrorCode = 3003
+	ErrAlreadyExists           ErrorCode = 3004
+	ErrEmailAlreadyExists      ErrorCode = 3005
+	ErrPhoneAlreadyExists      ErrorCode = 3006
+	ErrUsernameAlreadyExists   ErrorCode = 3007
+	ErrRoleAlreadyExists       ErrorCode = 3008
+	ErrPermissionAlreadyExists ErrorCode = 3009
+)
 
-	// Business logic errors (4000-4099)
-	ErrBusinessRule ErrorCode = 4000 + iota
+// Business logic errors (4000-4099)
+const (
+	ErrBusinessRule      ErrorCode = 4004
+	ErrNoVerifiedChannel ErrorCode = 4005
+	ErrAlreadyVerified   ErrorCode = 4006
+)
 
-	// System errors (5000-5099)
-	ErrInternalServer ErrorCode = 5000 + iota
+// System errors (5000-5099)
+const (
+	ErrInternalServer ErrorCode = 5005
 )
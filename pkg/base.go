@@ -6,14 +6,25 @@ import (
 	"go-api-starter/pkg/config"
 	"go-api-starter/pkg/database"
 	"go-api-starter/pkg/logger"
+	"go-api-starter/pkg/metrics"
+	"go-api-starter/pkg/migrations"
+	"go-api-starter/pkg/tracing"
+	"go-api-starter/pkg/utils"
 
 	"github.com/samber/do/v2"
 )
 
 var BasePackage = do.Package(
 	do.Lazy(config.NewConfig),
+	do.Lazy(config.NewReloader),
 	do.Lazy(cli.NewCLI),
 	do.Lazy(logger.NewLogger),
+	do.Lazy(tracing.NewProvider),
 	do.Lazy(database.NewPostgresql),
+	do.Lazy(database.NewTxManager),
+	do.Lazy(migrations.NewMigrator),
 	do.Lazy(cache.NewRedis),
+	do.Lazy(metrics.NewMetrics),
+	do.Lazy(utils.NewTokenService),
+	do.Lazy(utils.NewSMSSender),
 )
@@ -4,8 +4,10 @@ import (
 	"context"
 	"fmt"
 	"go-api-starter/pkg/config"
+	"go-api-starter/pkg/tracing"
 	"strconv"
 
+	"github.com/redis/go-redis/extra/redisotel/v9"
 	"github.com/redis/go-redis/v9"
 	"github.com/samber/do/v2"
 )
@@ -17,6 +19,7 @@ type Redis struct {
 func NewRedis(injector do.Injector) (*Redis, error) {
 
 	appConfig := do.MustInvoke[*config.Config](injector)
+	do.MustInvoke[*tracing.Provider](injector)
 	cfg := appConfig.Redis
 
 	client := redis.NewClient(&redis.Options{
@@ -30,7 +33,31 @@ func NewRedis(injector do.Injector) (*Redis, error) {
 		return nil, fmt.Errorf("redis ping error: %w", err)
 	}
 
+	// Statements are not recorded because values such as OTP codes are written to Redis
+	if err := redisotel.InstrumentTracing(client, redisotel.WithDBStatement(false)); err != nil {
+		return nil, fmt.Errorf("redis tracing error: %w", err)
+	}
+
 	return &Redis{
 		client: client,
 	}, nil
 }
+
+func (r *Redis) Client() *redis.Client {
+	return r.client
+}
+
+func (r *Redis) HealthCheckWithContext(ctx context.Context) error {
+	if err := r.client.Ping(ctx).Err(); err != nil {
+		return fmt.Errorf("redis health check failed: %w", err)
+	}
+	return nil
+}
+
+func (r *Redis) Shutdown(context.Context) error {
+	if r.client != nil {
+		return r.client.Close()
+	}
+
+	return nil
+}
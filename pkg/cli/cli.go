@@ -3,16 +3,19 @@ package cli
 import (
 	"context"
 	"go-api-starter/pkg/config"
+	"go-api-starter/pkg/migrations"
 	"net/http"
-	"os"
 	"os/signal"
+	"sync"
 	"syscall"
+	"time"
 
 	"github.com/rs/zerolog"
 	"github.com/samber/do/v2"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
+	"google.golang.org/grpc"
 
+	authGRPCRouter "go-api-starter/modules/auth/router/grpc"
 	authHTTPRouter "go-api-starter/modules/auth/router/http"
 	serverService "go-api-starter/pkg/server"
 )
@@ -36,8 +39,8 @@ func NewCLI(i do.Injector) (*CLI, error) {
 		Short:   "AnawimEnglish API",
 		Version: cli.config.App.Version,
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
-			// Re-unmarshal config to pick up flag values
-			return viper.Unmarshal(cli.config)
+			// Re-read config to pick up flag values, then validate the result
+			return cli.config.Load()
 		},
 	}
 
@@ -62,6 +65,9 @@ func (cli *CLI) setupCommands() {
 	// Add serve command
 	cli.rootCommand.AddCommand(cli.newServeCommand())
 
+	// Add migrate command
+	cli.rootCommand.AddCommand(cli.newMigrateCommand())
+
 }
 
 // newServeCommand creates the serve command.
@@ -72,19 +78,43 @@ func (cli *CLI) newServeCommand() *cobra.Command {
 		Run: func(cmd *cobra.Command, args []string) {
 			// Get the HTTP server from the dependency injection container
 			httpServer := do.MustInvoke[*serverService.HTTPServer](cli.injector)
+			grpcServer := do.MustInvoke[*serverService.GRPCServer](cli.injector)
 			logger := do.MustInvoke[*zerolog.Logger](cli.injector)
 
+			if cli.config.Postgresql.AutoMigrate {
+				applied, err := do.MustInvoke[*migrations.Migrator](cli.injector).Up(cmd.Context())
+				if err != nil {
+					logger.Fatal().Err(err).Msg("Failed to apply migrations")
+				}
+				logger.Info().Int("applied", applied).Msg("Database migrations are up to date")
+			}
+
 			// Register routes
+			healthHandler := do.MustInvoke[*serverService.HealthHandler](cli.injector)
+			healthHandler.Register(httpServer.Engine)
+
+			var metricsServer *serverService.MetricsServer
+			if cli.config.Metrics.Enabled {
+				metricsServer = do.MustInvoke[*serverService.MetricsServer](cli.injector)
+				metricsServer.Register(httpServer.Engine)
+			}
+
 			auth := do.MustInvoke[*authHTTPRouter.AuthHTTPRouter](cli.injector)
 			auth.Register(httpServer.Engine)
 
-			// Setup graceful shutdown
-			_, cancel := context.WithCancel(context.Background())
-			defer cancel()
+			websocketHub := do.MustInvoke[*serverService.WebSocketHub](cli.injector)
+			websocketHub.Register(httpServer.Engine)
+
+			authGRPC := do.MustInvoke[*authGRPCRouter.AuthGRPCRouter](cli.injector)
+			authGRPC.Register(grpcServer)
 
 			// Setup signal handling
-			sigChan := make(chan os.Signal, 1)
-			signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+			ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+			defer stop()
+
+			// Reload runtime-safe settings on SIGHUP or config file changes
+			reloader := do.MustInvoke[*config.Reloader](cli.injector)
+			reloader.Start(ctx)
 
 			// Start server in goroutine
 			go func() {
@@ -94,13 +124,82 @@ func (cli *CLI) newServeCommand() *cobra.Command {
 				}
 			}()
 
+			go func() {
+				logger.Info().Msg("Starting gRPC server...")
+				if err := grpcServer.Start(); err != nil && err != grpc.ErrServerStopped {
+					logger.Fatal().Err(err).Msg("Failed to start gRPC server")
+				}
+			}()
+
+			if metricsServer != nil && metricsServer.Separate() {
+				go func() {
+					if err := metricsServer.Start(); err != nil && err != http.ErrServerClosed {
+						logger.Fatal().Err(err).Msg("Failed to start metrics server")
+					}
+				}()
+			}
+
 			// Wait for signal
-			<-sigChan
+			<-ctx.Done()
+			stop()
 			logger.Info().Msg("Shutting down...")
+
+			cli.shutdown(logger, healthHandler, httpServer, grpcServer, websocketHub)
 		},
 	}
 }
 
+// shutdown first reports not ready for server.shutdown_delay so load balancers stop sending traffic,
+// then drains the servers within server.shutdown_timeout and releases every service held by the
+// injector, such as the Postgres pool and the Redis client, within another server.shutdown_timeout.
+func (cli *CLI) shutdown(
+	logger *zerolog.Logger,
+	healthHandler *serverService.HealthHandler,
+	httpServer *serverService.HTTPServer,
+	grpcServer *serverService.GRPCServer,
+	websocketHub *serverService.WebSocketHub,
+) {
+	healthHandler.MarkShuttingDown()
+	grpcServer.Health.Shutdown()
+	time.Sleep(time.Duration(cli.config.Server.ShutdownDelay) * time.Second)
+
+	timeout := time.Duration(cli.config.Server.ShutdownTimeout) * time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	// WebSocket connections are hijacked, so http.Server.Shutdown does not wait for them
+	if err := websocketHub.Shutdown(ctx); err != nil {
+		logger.Error().Err(err).Msg("Failed to close WebSocket connections")
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		if err := httpServer.Shutdown(ctx); err != nil {
+			logger.Error().Err(err).Msg("HTTP server did not drain in time")
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		if err := grpcServer.Shutdown(ctx); err != nil {
+			logger.Error().Err(err).Msg("gRPC server did not drain in time")
+		}
+	}()
+	wg.Wait()
+
+	// Draining may have used up the whole timeout, so releasing services gets a budget of its own
+	releaseCtx, releaseCancel := context.WithTimeout(context.Background(), timeout)
+	defer releaseCancel()
+
+	if report := cli.injector.ShutdownWithContext(releaseCtx); !report.Succeed {
+		logger.Error().Str("errors", report.Error()).Msg("Failed to shut down services")
+		return
+	}
+
+	logger.Info().Msg("Shutdown complete")
+}
+
 // RootCommand returns the root cobra command.
 func (cli *CLI) RootCommand() *cobra.Command {
 	return cli.rootCommand
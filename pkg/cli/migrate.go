@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"text/tabwriter"
+
+	"go-api-starter/pkg/constants"
+	"go-api-starter/pkg/migrations"
+
+	"github.com/samber/do/v2"
+	"github.com/spf13/cobra"
+)
+
+// newMigrateCommand creates the migrate command and its subcommands.
+func (cli *CLI) newMigrateCommand() *cobra.Command {
+	command := &cobra.Command{
+		Use:   "migrate",
+		Short: "Manage database migrations",
+	}
+
+	command.AddCommand(
+		cli.newMigrateUpCommand(),
+		cli.newMigrateDownCommand(),
+		cli.newMigrateStatusCommand(),
+		cli.newMigrateCreateCommand(),
+	)
+
+	return command
+}
+
+func (cli *CLI) newMigrateUpCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "up",
+		Short: "Apply all pending migrations",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := context.WithTimeout(cmd.Context(), constants.LongTimeout)
+			defer cancel()
+
+			applied, err := do.MustInvoke[*migrations.Migrator](cli.injector).Up(ctx)
+			if err != nil {
+				return err
+			}
+			cmd.Printf("Applied %d migration(s)\n", applied)
+			return nil
+		},
+	}
+}
+
+func (cli *CLI) newMigrateDownCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "down [steps]",
+		Short: "Roll back the last applied migrations (1 by default)",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			steps := 1
+			if len(args) == 1 {
+				n, err := strconv.Atoi(args[0])
+				if err != nil || n < 1 {
+					return fmt.Errorf("steps must be a positive number, got %q", args[0])
+				}
+				steps = n
+			}
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), constants.LongTimeout)
+			defer cancel()
+
+			rolledBack, err := do.MustInvoke[*migrations.Migrator](cli.injector).Down(ctx, steps)
+			if err != nil {
+				return err
+			}
+			cmd.Printf("Rolled back %d migration(s)\n", rolledBack)
+			return nil
+		},
+	}
+}
+
+func (cli *CLI) newMigrateStatusCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Show applied and pending migrations",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := context.WithTimeout(cmd.Context(), constants.LongTimeout)
+			defer cancel()
+
+			statuses, err := do.MustInvoke[*migrations.Migrator](cli.injector).Status(ctx)
+			if err != nil {
+				return err
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			_, _ = fmt.Fprintln(w, "MODULE\tVERSION\tNAME\tSTATUS")
+			for _, status := range statuses {
+				state := "pending"
+				switch {
+				case status.Missing:
+					state = "applied " + status.AppliedAt.Format("2006-01-02 15:04:05") + " (files missing)"
+				case status.AppliedAt != nil:
+					state = "applied " + status.AppliedAt.Format("2006-01-02 15:04:05")
+				}
+				_, _ = fmt.Fprintf(w, "%s\t%06d\t%s\t%s\n", status.Module, status.Version, status.Name, state)
+			}
+			return w.Flush()
+		},
+	}
+}
+
+func (cli *CLI) newMigrateCreateCommand() *cobra.Command {
+	var module string
+
+	command := &cobra.Command{
+		Use:   "create <name>",
+		Short: "Create empty up and down migration files for a module",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			paths, err := migrations.Create(do.MustInvoke[[]migrations.Source](cli.injector), module, args[0])
+			for _, path := range paths {
+				cmd.Printf("Created %s\n", path)
+			}
+			return err
+		},
+	}
+	command.Flags().StringVar(&module, "module", "", "Module the migration belongs to (optional when only one module has migrations)")
+
+	return command
+}
@@ -0,0 +1,155 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-api-starter/pkg/constants"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// field describes a leaf of the Config struct.
+type field struct {
+	key   string
+	index []int
+	typ   reflect.Type
+	tag   reflect.StructTag
+}
+
+// configFields walks Config and returns its leaf fields keyed by their dotted path.
+func configFields() []field {
+	return collectFields(reflect.TypeOf(Config{}), "", nil)
+}
+
+func collectFields(t reflect.Type, prefix string, index []int) []field {
+	var fields []field
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		key := prefix + sf.Tag.Get("mapstructure")
+		fieldIndex := append(append([]int{}, index...), i)
+
+		if sf.Type.Kind() == reflect.Struct {
+			fields = append(fields, collectFields(sf.Type, key+".", fieldIndex)...)
+			continue
+		}
+		fields = append(fields, field{key: key, index: fieldIndex, typ: sf.Type, tag: sf.Tag})
+	}
+	return fields
+}
+
+func (f field) reloadable() bool {
+	return f.tag.Get("reload") == "true"
+}
+
+// envKey returns the environment variable viper reads for the field, e.g. POSTGRESQL_PASSWORD.
+func (f field) envKey() string {
+	return strings.ToUpper(strings.ReplaceAll(f.key, ".", "_"))
+}
+
+// codeDefaults holds the defaults of fields that take them from constants used before the
+// field existed, instead of from a default tag, so the two cannot drift apart.
+var codeDefaults = map[string]any{
+	"auth.max_login_attempts": constants.MaxLoginAttempts,
+	"auth.block_duration":     int(constants.BlockDuration / time.Second),
+}
+
+// defaultValue parses the default tag into the type of the field.
+func (f field) defaultValue() (any, error) {
+	if value, ok := codeDefaults[f.key]; ok {
+		return value, nil
+	}
+	raw := f.tag.Get("default")
+
+	switch f.typ.Kind() {
+	case reflect.String:
+		return raw, nil
+	case reflect.Bool:
+		if raw == "" {
+			return false, nil
+		}
+		return strconv.ParseBool(raw)
+	case reflect.Int:
+		if raw == "" {
+			return 0, nil
+		}
+		return strconv.Atoi(raw)
+	case reflect.Float64:
+		if raw == "" {
+			return float64(0), nil
+		}
+		return strconv.ParseFloat(raw, 64)
+	case reflect.Slice:
+		if raw == "" {
+			return []string{}, nil
+		}
+		return strings.Split(raw, ","), nil
+	case reflect.Map:
+		return map[string]string{}, nil
+	default:
+		return nil, fmt.Errorf("config field %s has unsupported type %s", f.key, f.typ)
+	}
+}
+
+// setDefaults registers the tag defaults with viper, which also makes every key known
+// to viper so nested keys can be overridden by environment variables.
+func setDefaults() error {
+	for _, f := range configFields() {
+		value, err := f.defaultValue()
+		if err != nil {
+			return fmt.Errorf("invalid default for %s: %w", f.key, err)
+		}
+		viper.SetDefault(f.key, value)
+	}
+	return nil
+}
+
+// registerFlag adds a flag for the field. Defaults are validated by setDefaults,
+// which runs first in NewConfig, so parse errors are not possible here.
+func registerFlag(flags *pflag.FlagSet, f field) {
+	value, _ := f.defaultValue()
+	usage := f.tag.Get("usage")
+
+	switch v := value.(type) {
+	case string:
+		flags.String(f.key, v, usage)
+	case bool:
+		flags.Bool(f.key, v, usage)
+	case int:
+		flags.Int(f.key, v, usage)
+	case float64:
+		flags.Float64(f.key, v, usage)
+	case []string:
+		flags.StringSlice(f.key, v, usage)
+	case map[string]string:
+		flags.StringToString(f.key, v, usage)
+	}
+}
+
+// loadSecretFiles reads string settings from the file named by <ENV_KEY>_FILE, e.g.
+// POSTGRESQL_PASSWORD_FILE=/run/secrets/db_password, as used by Docker and Kubernetes secrets.
+// The file content overrides every other source.
+func loadSecretFiles() error {
+	for _, f := range configFields() {
+		if f.typ.Kind() != reflect.String {
+			continue
+		}
+
+		path := os.Getenv(f.envKey() + "_FILE")
+		if path == "" {
+			continue
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("error reading %s_FILE: %w", f.envKey(), err)
+		}
+		viper.Set(f.key, strings.TrimRight(string(content), "\r\n"))
+	}
+	return nil
+}
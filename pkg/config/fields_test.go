@@ -0,0 +1,45 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"go-api-starter/pkg/constants"
+)
+
+func TestCodeDefaults(t *testing.T) {
+	fields := make(map[string]field)
+	for _, f := range configFields() {
+		fields[f.key] = f
+	}
+
+	for key := range codeDefaults {
+		f, ok := fields[key]
+		if !ok {
+			t.Fatalf("code default %s has no config field", key)
+		}
+		if f.tag.Get("default") != "" {
+			t.Fatalf("%s has both a code default and a default tag", key)
+		}
+	}
+
+	tests := []struct {
+		key  string
+		want any
+	}{
+		{key: "auth.max_login_attempts", want: constants.MaxLoginAttempts},
+		{key: "auth.block_duration", want: int(constants.BlockDuration / time.Second)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			got, err := fields[tt.key].defaultValue()
+			if err != nil {
+				t.Fatalf("defaultValue: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("default = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
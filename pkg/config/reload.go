@@ -0,0 +1,160 @@
+package config
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"syscall"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/rs/zerolog"
+	"github.com/samber/do/v2"
+	"github.com/spf13/viper"
+)
+
+// Reloadable is implemented by services that apply runtime-safe settings when the
+// configuration is reloaded. Every invoked service of the injector is checked for it.
+type Reloadable interface {
+	OnConfigReload(cfg *Config)
+}
+
+// Reloader re-reads the configuration on SIGHUP or when the config file changes and applies
+// the fields tagged reload:"true". Changes to any other field need a restart: they are
+// logged and ignored, so the running process keeps a consistent configuration.
+type Reloader struct {
+	injector do.Injector
+	logger   *zerolog.Logger
+	mu       sync.Mutex
+	current  atomic.Pointer[Config]
+}
+
+func NewReloader(i do.Injector) (*Reloader, error) {
+	reloader := &Reloader{
+		injector: i,
+		logger:   do.MustInvoke[*zerolog.Logger](i),
+	}
+	reloader.current.Store(do.MustInvoke[*Config](i))
+
+	return reloader, nil
+}
+
+// Current returns the latest applied configuration. The *Config provided by the injector
+// keeps the startup values, so code reading reloadable fields should use Current.
+func (r *Reloader) Current() *Config {
+	return r.current.Load()
+}
+
+// FeatureEnabled reports whether the named feature flag is on in the latest applied configuration.
+func (r *Reloader) FeatureEnabled(name string) bool {
+	return r.Current().FeatureEnabled(name)
+}
+
+// Start watches the config file, if app.watch_config is set, and SIGHUP until ctx is done.
+func (r *Reloader) Start(ctx context.Context) {
+	if r.Current().App.WatchConfig && viper.ConfigFileUsed() != "" {
+		viper.OnConfigChange(func(fsnotify.Event) {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			r.apply()
+		})
+		viper.WatchConfig()
+	}
+
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+
+	go func() {
+		defer signal.Stop(hangup)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-hangup:
+				r.Reload()
+			}
+		}
+	}()
+}
+
+// Reload reads the config file again and applies the runtime-safe changes.
+func (r *Reloader) Reload() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := viper.ReadInConfig(); err != nil {
+		r.logger.Error().Err(err).Msg("Failed to read config file for reload")
+		return
+	}
+	r.apply()
+}
+
+func (r *Reloader) apply() {
+	next := &Config{}
+	if err := next.Load(); err != nil {
+		r.logger.Error().Err(err).Msg("Rejected config reload")
+		return
+	}
+
+	current := r.current.Load()
+	if rejected := changedFields(current, next, false); len(rejected) > 0 {
+		r.logger.Warn().Strs("keys", rejected).Msg("Ignoring config changes that require a restart")
+	}
+
+	changed := changedFields(current, next, true)
+	if len(changed) == 0 {
+		return
+	}
+
+	// Start from the running configuration and only take over the reloadable fields
+	applied := *current
+	target := reflect.ValueOf(&applied).Elem()
+	source := reflect.ValueOf(next).Elem()
+	for _, f := range configFields() {
+		if f.reloadable() {
+			target.FieldByIndex(f.index).Set(source.FieldByIndex(f.index))
+		}
+	}
+	r.current.Store(&applied)
+
+	// The logger is a plain *zerolog.Logger, its level is global
+	if level, err := zerolog.ParseLevel(applied.Logger.Level); err == nil {
+		zerolog.SetGlobalLevel(level)
+	}
+
+	r.notify(&applied)
+	r.logger.Info().Strs("keys", changed).Msg("Config reloaded")
+}
+
+// notify calls OnConfigReload on every invoked service implementing Reloadable.
+func (r *Reloader) notify(cfg *Config) {
+	for _, service := range r.injector.ListInvokedServices() {
+		instance, err := do.InvokeNamed[any](r.injector, service.Service)
+		if err != nil {
+			continue
+		}
+		if subscriber, ok := instance.(Reloadable); ok {
+			subscriber.OnConfigReload(cfg)
+		}
+	}
+}
+
+// changedFields returns the keys whose value differs between a and b, limited to the
+// reloadable or the non reloadable fields.
+func changedFields(a, b *Config, reloadable bool) []string {
+	va := reflect.ValueOf(a).Elem()
+	vb := reflect.ValueOf(b).Elem()
+
+	var keys []string
+	for _, f := range configFields() {
+		if f.reloadable() != reloadable {
+			continue
+		}
+		if !reflect.DeepEqual(va.FieldByIndex(f.index).Interface(), vb.FieldByIndex(f.index).Interface()) {
+			keys = append(keys, f.key)
+		}
+	}
+	return keys
+}
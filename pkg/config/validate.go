@@ -0,0 +1,130 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Validate checks the validate tags of every field plus the rules that span several fields,
+// and reports all violations at once.
+//
+// Supported rules:
+//   - required:    the value must not be empty (or, for lists, have no items)
+//   - port:        an integer between 1 and 65535
+//   - min=N,max=N: bounds of an int or float
+//   - oneof=a b c: allowed values of a string
+func (cs *Config) Validate() error {
+	var errs []error
+
+	value := reflect.ValueOf(cs).Elem()
+	for _, f := range configFields() {
+		rules := f.tag.Get("validate")
+		if rules == "" {
+			continue
+		}
+		for _, rule := range strings.Split(rules, ",") {
+			if err := checkRule(value.FieldByIndex(f.index), rule); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", f.key, err))
+			}
+		}
+	}
+
+	errs = append(errs, cs.validateDependencies()...)
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid configuration:\n%w", errors.Join(errs...))
+	}
+	return nil
+}
+
+func checkRule(v reflect.Value, rule string) error {
+	name, arg, _ := strings.Cut(rule, "=")
+
+	switch name {
+	case "required":
+		if v.IsZero() || (v.Kind() == reflect.Slice && v.Len() == 0) {
+			return errors.New("is required")
+		}
+	case "port":
+		if port := v.Int(); port < 1 || port > 65535 {
+			return fmt.Errorf("must be a port between 1 and 65535, got %d", port)
+		}
+	case "min", "max":
+		bound, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			return fmt.Errorf("invalid %s rule %q", name, arg)
+		}
+		number := toFloat(v)
+		if name == "min" && number < bound {
+			return fmt.Errorf("must be at least %s, got %v", arg, number)
+		}
+		if name == "max" && number > bound {
+			return fmt.Errorf("must be at most %s, got %v", arg, number)
+		}
+	case "oneof":
+		allowed := strings.Fields(arg)
+		if !slices.Contains(allowed, v.String()) {
+			return fmt.Errorf("must be one of %s, got %q", strings.Join(allowed, ", "), v.String())
+		}
+	default:
+		return fmt.Errorf("unknown validation rule %q", name)
+	}
+	return nil
+}
+
+func toFloat(v reflect.Value) float64 {
+	if v.Kind() == reflect.Float64 {
+		return v.Float()
+	}
+	return float64(v.Int())
+}
+
+// validateDependencies checks settings that are only required depending on other settings.
+func (cs *Config) validateDependencies() []error {
+	var errs []error
+
+	switch cs.JWT.Algorithm {
+	case "HS256":
+		if cs.JWT.Secret == "" {
+			errs = append(errs, errors.New("jwt.secret: is required for HS256"))
+		}
+	case "RS256", "EdDSA":
+		if cs.JWT.PublicKeyPath == "" {
+			errs = append(errs, fmt.Errorf("jwt.public_key_path: is required for %s", cs.JWT.Algorithm))
+		}
+	}
+
+	for _, cidr := range cs.Server.TrustedProxies {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			errs = append(errs, fmt.Errorf("server.trusted_proxies: invalid CIDR %q", cidr))
+		}
+	}
+
+	if cs.SMS.Provider == "webhook" && cs.SMS.WebhookURL == "" {
+		errs = append(errs, errors.New("sms.webhook_url: is required for the webhook provider"))
+	}
+
+	if cs.Metrics.Enabled && !strings.HasPrefix(cs.Metrics.Path, "/") {
+		errs = append(errs, errors.New("metrics.path: must start with /"))
+	}
+
+	if cs.Tracing.Enabled {
+		switch cs.Tracing.Exporter {
+		case "otlp":
+			if cs.Tracing.Endpoint == "" {
+				errs = append(errs, errors.New("tracing.endpoint: is required for the otlp exporter"))
+			}
+		case "file":
+			if cs.Tracing.FilePath == "" {
+				errs = append(errs, errors.New("tracing.file_path: is required for the file exporter"))
+			}
+		}
+	}
+
+	return errs
+}
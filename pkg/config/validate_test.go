@@ -0,0 +1,141 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// defaultConfig returns a Config holding the default tag of every field, as Load does without
+// a config file, plus the HS256 secret that has no default.
+func defaultConfig(t *testing.T) *Config {
+	t.Helper()
+
+	cfg := &Config{}
+	value := reflect.ValueOf(cfg).Elem()
+	for _, f := range configFields() {
+		def, err := f.defaultValue()
+		if err != nil {
+			t.Fatalf("default of %s: %v", f.key, err)
+		}
+		target := value.FieldByIndex(f.index)
+		if target.Kind() == reflect.Map {
+			continue
+		}
+		target.Set(reflect.ValueOf(def).Convert(target.Type()))
+	}
+	cfg.JWT.Secret = "secret"
+	return cfg
+}
+
+func TestCheckRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   any
+		rule    string
+		wantErr bool
+	}{
+		{name: "required string", value: "x", rule: "required"},
+		{name: "required empty string", value: "", rule: "required", wantErr: true},
+		{name: "required zero int", value: 0, rule: "required", wantErr: true},
+		{name: "required list", value: []string{"a"}, rule: "required"},
+		{name: "required empty list", value: []string{}, rule: "required", wantErr: true},
+		{name: "port", value: 8080, rule: "port"},
+		{name: "port zero", value: 0, rule: "port", wantErr: true},
+		{name: "port too large", value: 65536, rule: "port", wantErr: true},
+		{name: "min", value: 1, rule: "min=1"},
+		{name: "below min", value: 0, rule: "min=1", wantErr: true},
+		{name: "max float", value: 1.0, rule: "max=1"},
+		{name: "above max float", value: 1.5, rule: "max=1", wantErr: true},
+		{name: "invalid bound", value: 1, rule: "min=a", wantErr: true},
+		{name: "oneof", value: "login", rule: "oneof=none login routes"},
+		{name: "not oneof", value: "all", rule: "oneof=none login routes", wantErr: true},
+		{name: "unknown rule", value: "x", rule: "email", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkRule(reflect.ValueOf(tt.value), tt.rule)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(cfg *Config)
+		// wantKeys are the config keys the error must mention, none when valid
+		wantKeys []string
+	}{
+		{name: "defaults", modify: func(*Config) {}},
+		{
+			name:     "hs256 without secret",
+			modify:   func(cfg *Config) { cfg.JWT.Secret = "" },
+			wantKeys: []string{"jwt.secret"},
+		},
+		{
+			name:     "rs256 without public key",
+			modify:   func(cfg *Config) { cfg.JWT.Algorithm = "RS256" },
+			wantKeys: []string{"jwt.public_key_path"},
+		},
+		{
+			name:     "invalid trusted proxy",
+			modify:   func(cfg *Config) { cfg.Server.TrustedProxies = []string{"10.0.0.0/8", "10.0.0.1"} },
+			wantKeys: []string{"server.trusted_proxies"},
+		},
+		{
+			name:     "webhook sms without url",
+			modify:   func(cfg *Config) { cfg.SMS.Provider = "webhook" },
+			wantKeys: []string{"sms.webhook_url"},
+		},
+		{
+			name:     "relative metrics path",
+			modify:   func(cfg *Config) { cfg.Metrics.Path = "metrics" },
+			wantKeys: []string{"metrics.path"},
+		},
+		{
+			name: "file tracing without path",
+			modify: func(cfg *Config) {
+				cfg.Tracing.Enabled = true
+				cfg.Tracing.Exporter = "file"
+				cfg.Tracing.FilePath = ""
+			},
+			wantKeys: []string{"tracing.file_path"},
+		},
+		{
+			name: "every violation is reported",
+			modify: func(cfg *Config) {
+				cfg.Server.Port = 0
+				cfg.Auth.VerificationPolicy = "always"
+				cfg.Tracing.SampleRatio = 2
+			},
+			wantKeys: []string{"server.port", "auth.verification_policy", "tracing.sample_ratio"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := defaultConfig(t)
+			tt.modify(cfg)
+
+			err := cfg.Validate()
+			if len(tt.wantKeys) == 0 {
+				if err != nil {
+					t.Fatalf("Validate: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("Validate succeeded, want errors for %v", tt.wantKeys)
+			}
+			for _, key := range tt.wantKeys {
+				if !strings.Contains(err.Error(), key+":") {
+					t.Errorf("error does not mention %s:\n%v", key, err)
+				}
+			}
+		})
+	}
+}
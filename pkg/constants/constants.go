@@ -8,10 +8,29 @@ const (
 
 	// OTP related keys
 	RedisKeyOTPChangePassword = RedisKeyPrefix + "otp_change_password:"
+	RedisKeyOTPRequests       = RedisKeyPrefix + "otp_requests:"
+	RedisKeyOTPVerifications  = RedisKeyPrefix + "otp_verifications:"
+
+	// Login attempt counters
+	RedisKeyLoginAttemptsIdentifier = RedisKeyPrefix + "login_attempts:identifier:"
+	RedisKeyLoginAttemptsIP         = RedisKeyPrefix + "login_attempts:ip:"
+
+	// Wrong current passwords given when changing the password
+	RedisKeyPasswordAttempts = RedisKeyPrefix + "password_attempts:user:"
+
+	// Nonces of signed internal requests
+	RedisKeyInternalNonce = RedisKeyPrefix + "internal_nonce:"
+
+	// Read-your-writes windows, keyed by the ID in the client's cookie
+	RedisKeyPrimaryReads = RedisKeyPrefix + "primary_reads:"
 )
 
 const (
-	TokenBlacklistKey = "token_blacklist:jti:"
+	TokenBlacklistKey   = "token_blacklist:jti:"
+	TokenUserRevokedKey = "token_blacklist:user:"
+
+	// Pub/sub channel announcing revoked tokens to every instance
+	TokenRevokedChannel = RedisKeyPrefix + "token_revoked"
 )
 
 const (
@@ -21,10 +40,57 @@ const (
 	ScopeTokenEmailVerification = "email_verification"
 )
 
-// Giới hạn login
+// Giới hạn login mặc định, dùng làm giá trị mặc định của auth.max_login_attempts và auth.block_duration
 const (
+	// Deprecated: read auth.max_login_attempts (config.AuthConfig.MaxLoginAttempts), which can be reloaded.
 	MaxLoginAttempts = 5
-	BlockDuration    = 15 * time.Minute
+	// Deprecated: read auth.block_duration (config.AuthConfig.BlockDuration), which can be reloaded.
+	BlockDuration = 15 * time.Minute
+)
+
+// Verification policies
+const (
+	VerificationPolicyNone   = "none"
+	VerificationPolicyLogin  = "login"
+	VerificationPolicyRoutes = "routes"
+)
+
+// Feature flags, bật bằng features.<tên>: true và áp dụng ngay khi reload config
+const (
+	FeatureDisableRegistration = "disable_registration" // tạm ngưng đăng ký tài khoản mới
+)
+
+// Role và permissions mặc định dùng cho các API quản trị
+const (
+	RoleAdmin = "admin"
+
+	PermissionUsersRead   = "users.read"
+	PermissionUsersWrite  = "users.write"
+	PermissionRolesManage = "roles.manage"
+)
+
+// Thời gian cache roles và permissions trong Redis; mọi thay đổi RBAC tăng version để bỏ cache cũ
+const (
+	RBACCacheTTL        = 1 * time.Hour
+	RedisKeyRBACVersion = RedisKeyPrefix + "rbac:version"
+)
+
+// Giới hạn gửi và xác thực OTP, chống dò mã và spam SMS
+const (
+	MaxOTPRequestsPerIdentifier = 5
+	MaxOTPRequestsPerIP         = 20
+	MaxOTPVerificationsPerIP    = 30
+	OTPRateLimitWindow          = 1 * time.Hour
+)
+
+// Số mật khẩu gần nhất không được dùng lại
+const (
+	PasswordHistoryLimit = 5
+)
+
+// Khoá advisory của Postgres, đảm bảo chỉ một tiến trình chạy migration tại một thời điểm
+const (
+	MigrationAdvisoryLockID int64 = 7164835920
 )
 
 // Timeout request
@@ -48,5 +114,6 @@ const (
 
 // Context Key constants
 const (
-	ContextTokenData = "token_data"
+	ContextTokenData       = "token_data"
+	ContextInternalService = "internal_service"
 )
@@ -2,20 +2,26 @@ package database
 
 import (
 	"go-api-starter/pkg/config"
+	"go-api-starter/pkg/tracing"
 	"context"
 	"fmt"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
 	"github.com/samber/do/v2"
 )
 
 type Postgresql struct {
-	pool *pgxpool.Pool
+	pool      *pgxpool.Pool
+	replicas  *replicaSet
+	stopWatch context.CancelFunc
 }
 
 func NewPostgresql(injector do.Injector) (*Postgresql, error) {
 	appConfig := do.MustInvoke[*config.Config](injector)
+	logger := do.MustInvoke[*zerolog.Logger](injector)
+	do.MustInvoke[*tracing.Provider](injector)
 	cfg := appConfig.Postgresql
 
 	connString := fmt.Sprintf(
@@ -31,6 +37,52 @@ func NewPostgresql(injector do.Injector) (*Postgresql, error) {
 		time.Duration(cfg.ConnMaxLifetime)*time.Second,
 	)
 
+	pool, err := newPool(connString, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := pool.Ping(context.Background()); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	db := &Postgresql{pool: pool}
+	if len(cfg.ReplicaDSNs) == 0 {
+		return db, nil
+	}
+
+	db.replicas = &replicaSet{logger: logger}
+	for i, dsn := range cfg.ReplicaDSNs {
+		replicaPool, err := newPool(dsn, cfg)
+		if err != nil {
+			db.replicas.close()
+			pool.Close()
+			return nil, fmt.Errorf("replica %d: %w", i, err)
+		}
+
+		connConfig := replicaPool.Config().ConnConfig
+		r := &replica{name: fmt.Sprintf("%s:%d", connConfig.Host, connConfig.Port), pool: replicaPool}
+		db.replicas.replicas = append(db.replicas.replicas, r)
+
+		// An unreachable replica does not stop startup, it only receives no reads until it recovers
+		if err := replicaPool.Ping(context.Background()); err != nil {
+			logger.Warn().Err(err).Str("replica", r.name).Msg("read replica is unhealthy, routing its reads to other pools")
+			continue
+		}
+		r.healthy.Store(true)
+	}
+
+	watchCtx, cancel := context.WithCancel(context.Background())
+	db.stopWatch = cancel
+	go db.replicas.watch(watchCtx, time.Duration(cfg.ReplicaCheckInterval)*time.Second)
+
+	return db, nil
+}
+
+// newPool creates a pool for connString with the pool settings of cfg.
+// Settings in connString are overridden by cfg.
+func newPool(connString string, cfg config.PostgresqlConfig) (*pgxpool.Pool, error) {
 	poolConfig, err := pgxpool.ParseConfig(connString)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse database config: %w", err)
@@ -41,24 +93,30 @@ func NewPostgresql(injector do.Injector) (*Postgresql, error) {
 	poolConfig.MaxConnLifetime = time.Duration(cfg.ConnMaxLifetime) * time.Second
 	poolConfig.HealthCheckPeriod = 1 * time.Minute
 	poolConfig.MaxConnIdleTime = 5 * time.Minute
+	poolConfig.ConnConfig.Tracer = tracing.NewPgxTracer()
 
 	pool, err := pgxpool.NewWithConfig(context.Background(), poolConfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create connection pool: %w", err)
 	}
-
-	if err := pool.Ping(context.Background()); err != nil {
-		pool.Close()
-		return nil, fmt.Errorf("failed to ping database: %w", err)
-	}
-
-	return &Postgresql{pool: pool}, nil
+	return pool, nil
 }
 
 func (db *Postgresql) Pool() *pgxpool.Pool {
 	return db.pool
 }
 
+// ReplicaPools returns the read replica pools keyed by host and port.
+func (db *Postgresql) ReplicaPools() map[string]*pgxpool.Pool {
+	pools := make(map[string]*pgxpool.Pool)
+	if db.replicas != nil {
+		for _, r := range db.replicas.replicas {
+			pools[r.name] = r.pool
+		}
+	}
+	return pools
+}
+
 func (db *Postgresql) HealthCheckWithContext(ctx context.Context) error {
 	if err := db.pool.Ping(ctx); err != nil {
 		return fmt.Errorf("database health check failed: %w", err)
@@ -67,6 +125,12 @@ func (db *Postgresql) HealthCheckWithContext(ctx context.Context) error {
 }
 
 func (db *Postgresql) Shutdown(context.Context) error {
+	if db.stopWatch != nil {
+		db.stopWatch()
+	}
+	if db.replicas != nil {
+		db.replicas.close()
+	}
 	if db.pool != nil {
 		db.pool.Close()
 	}
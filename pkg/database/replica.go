@@ -0,0 +1,239 @@
+package database
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"time"
+	"unicode"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+)
+
+// replica is a read-only pool that is skipped while its health check fails.
+type replica struct {
+	name    string
+	pool    *pgxpool.Pool
+	healthy atomic.Bool
+}
+
+// replicaSet picks healthy replicas round-robin.
+type replicaSet struct {
+	replicas []*replica
+	next     atomic.Uint64
+	logger   *zerolog.Logger
+}
+
+// pick returns the next healthy replica, or nil when none is healthy.
+func (s *replicaSet) pick() *replica {
+	n := len(s.replicas)
+	for range n {
+		r := s.replicas[s.next.Add(1)%uint64(n)]
+		if r.healthy.Load() {
+			return r
+		}
+	}
+	return nil
+}
+
+// watch pings every replica each interval until ctx is done, ejecting the ones that
+// fail and bringing them back once they answer again.
+func (s *replicaSet) watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, r := range s.replicas {
+				s.check(ctx, r, interval)
+			}
+		}
+	}
+}
+
+func (s *replicaSet) check(ctx context.Context, r *replica, timeout time.Duration) {
+	pingCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := r.pool.Ping(pingCtx)
+	if ctx.Err() != nil {
+		return
+	}
+
+	switch healthy := err == nil; {
+	case !healthy && r.healthy.Swap(false):
+		s.logger.Warn().Err(err).Str("replica", r.name).Msg("read replica is unhealthy, routing its reads to other pools")
+	case healthy && !r.healthy.Swap(true):
+		s.logger.Info().Str("replica", r.name).Msg("read replica is healthy again")
+	}
+}
+
+func (s *replicaSet) close() {
+	for _, r := range s.replicas {
+		r.pool.Close()
+	}
+}
+
+// session records whether the current request has written to the primary, and until when
+// its reads stay on the primary because the same client wrote in an earlier request.
+type session struct {
+	wrote        atomic.Bool
+	primaryUntil atomic.Int64 // unix milliseconds
+}
+
+type sessionContextKey struct{}
+
+type primaryContextKey struct{}
+
+// WithSession returns a context that remembers writes made through it, so later reads
+// with the same context go to the primary and see them despite replication lag.
+func WithSession(ctx context.Context) context.Context {
+	return context.WithValue(ctx, sessionContextKey{}, &session{})
+}
+
+// WithPrimary returns a context whose reads always go to the primary.
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryContextKey{}, true)
+}
+
+func sessionFromContext(ctx context.Context) *session {
+	s, _ := ctx.Value(sessionContextKey{}).(*session)
+	return s
+}
+
+// ReadFromPrimaryUntil sends the reads of the session of ctx to the primary until t, so a client
+// sees its own writes made by earlier requests despite replication lag.
+func ReadFromPrimaryUntil(ctx context.Context, t time.Time) {
+	if s := sessionFromContext(ctx); s != nil {
+		s.primaryUntil.Store(t.UnixMilli())
+	}
+}
+
+// Wrote reports whether the session of ctx has written to the primary.
+func Wrote(ctx context.Context) bool {
+	s := sessionFromContext(ctx)
+	return s != nil && s.wrote.Load()
+}
+
+// markWrite records a write in the session of ctx, if any.
+func markWrite(ctx context.Context) {
+	if s := sessionFromContext(ctx); s != nil {
+		s.wrote.Store(true)
+	}
+}
+
+// readsFromPrimary reports whether ctx asks for reads from the primary.
+func readsFromPrimary(ctx context.Context) bool {
+	if forced, _ := ctx.Value(primaryContextKey{}).(bool); forced {
+		return true
+	}
+	s := sessionFromContext(ctx)
+	return s != nil && (s.wrote.Load() || time.Now().UnixMilli() < s.primaryUntil.Load())
+}
+
+// primaryQuerier marks the session as written before running a statement on the primary.
+// Read-only statements, see isReadOnly, are not considered writes.
+type primaryQuerier struct {
+	pool    *pgxpool.Pool
+	session *session
+}
+
+func (q primaryQuerier) track(sql string) {
+	if !isReadOnly(sql) {
+		q.session.wrote.Store(true)
+	}
+}
+
+func (q primaryQuerier) Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error) {
+	q.track(sql)
+	return q.pool.Exec(ctx, sql, arguments...)
+}
+
+func (q primaryQuerier) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	q.track(sql)
+	return q.pool.Query(ctx, sql, args...)
+}
+
+func (q primaryQuerier) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	q.track(sql)
+	return q.pool.QueryRow(ctx, sql, args...)
+}
+
+func (q primaryQuerier) SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults {
+	q.session.wrote.Store(true)
+	return q.pool.SendBatch(ctx, b)
+}
+
+func (q primaryQuerier) CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error) {
+	q.session.wrote.Store(true)
+	return q.pool.CopyFrom(ctx, tableName, columnNames, rowSrc)
+}
+
+// replicaQuerier serves read-only statements from a replica and sends everything else,
+// such as SELECT ... FOR UPDATE, to the primary.
+type replicaQuerier struct {
+	replica *pgxpool.Pool
+	primary Querier
+}
+
+func (q replicaQuerier) Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error) {
+	if isReadOnly(sql) {
+		return q.replica.Exec(ctx, sql, arguments...)
+	}
+	return q.primary.Exec(ctx, sql, arguments...)
+}
+
+func (q replicaQuerier) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	if isReadOnly(sql) {
+		return q.replica.Query(ctx, sql, args...)
+	}
+	return q.primary.Query(ctx, sql, args...)
+}
+
+func (q replicaQuerier) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	if isReadOnly(sql) {
+		return q.replica.QueryRow(ctx, sql, args...)
+	}
+	return q.primary.QueryRow(ctx, sql, args...)
+}
+
+func (q replicaQuerier) SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults {
+	return q.primary.SendBatch(ctx, b)
+}
+
+func (q replicaQuerier) CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error) {
+	return q.primary.CopyFrom(ctx, tableName, columnNames, rowSrc)
+}
+
+var (
+	// lockingClause matches the row locks of SELECT, which a replica cannot take
+	lockingClause = regexp.MustCompile(`(?i)\bFOR\s+(UPDATE|NO\s+KEY\s+UPDATE|SHARE|KEY\s+SHARE)\b`)
+	// modifyingStatement matches data-modifying statements, which may be nested in a WITH query
+	modifyingStatement = regexp.MustCompile(`(?i)\b(INSERT|UPDATE|DELETE|MERGE)\b`)
+)
+
+// isReadOnly reports whether sql is a SELECT or WITH query that neither locks nor modifies rows.
+// It errs on the side of the primary: a keyword inside a literal or identifier makes it false.
+func isReadOnly(sql string) bool {
+	sql = strings.TrimSpace(sql)
+	end := strings.IndexFunc(sql, func(r rune) bool { return !unicode.IsLetter(r) })
+	if end < 0 {
+		end = len(sql)
+	}
+
+	switch keyword := sql[:end]; {
+	case strings.EqualFold(keyword, "SELECT"):
+		return !lockingClause.MatchString(sql)
+	case strings.EqualFold(keyword, "WITH"):
+		return !lockingClause.MatchString(sql) && !modifyingStatement.MatchString(sql)
+	default:
+		return false
+	}
+}
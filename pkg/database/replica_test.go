@@ -0,0 +1,127 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func newTestReplicaSet(healthy ...bool) *replicaSet {
+	set := &replicaSet{}
+	for i, h := range healthy {
+		r := &replica{name: string(rune('a' + i))}
+		r.healthy.Store(h)
+		set.replicas = append(set.replicas, r)
+	}
+	return set
+}
+
+func TestReplicaSetPick(t *testing.T) {
+	tests := []struct {
+		name    string
+		healthy []bool
+		picks   int
+		want    []string
+	}{
+		{name: "round-robin", healthy: []bool{true, true, true}, picks: 6, want: []string{"b", "c", "a", "b", "c", "a"}},
+		{name: "unhealthy replica is skipped", healthy: []bool{true, false, true}, picks: 4, want: []string{"c", "a", "c", "a"}},
+		{name: "single healthy replica", healthy: []bool{false, true}, picks: 3, want: []string{"b", "b", "b"}},
+		{name: "none healthy", healthy: []bool{false, false}, picks: 2, want: []string{"", ""}},
+		{name: "none configured", healthy: nil, picks: 1, want: []string{""}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set := newTestReplicaSet(tt.healthy...)
+			for i := range tt.picks {
+				got := ""
+				if r := set.pick(); r != nil {
+					got = r.name
+				}
+				if got != tt.want[i] {
+					t.Errorf("pick %d = %q, want %q", i, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestReadsFromPrimary(t *testing.T) {
+	tests := []struct {
+		name string
+		ctx  func() context.Context
+		want bool
+	}{
+		{name: "no session", ctx: context.Background},
+		{name: "session without writes", ctx: func() context.Context { return WithSession(context.Background()) }},
+		{name: "forced", ctx: func() context.Context { return WithPrimary(context.Background()) }, want: true},
+		{
+			name: "session that wrote",
+			ctx: func() context.Context {
+				ctx := WithSession(context.Background())
+				markWrite(ctx)
+				return ctx
+			},
+			want: true,
+		},
+		{
+			name: "earlier write within the window",
+			ctx: func() context.Context {
+				ctx := WithSession(context.Background())
+				ReadFromPrimaryUntil(ctx, time.Now().Add(time.Minute))
+				return ctx
+			},
+			want: true,
+		},
+		{
+			name: "earlier write past the window",
+			ctx: func() context.Context {
+				ctx := WithSession(context.Background())
+				ReadFromPrimaryUntil(ctx, time.Now().Add(-time.Second))
+				return ctx
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := readsFromPrimary(tt.ctx()); got != tt.want {
+				t.Errorf("readsFromPrimary = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsReadOnly(t *testing.T) {
+	tests := []struct {
+		sql  string
+		want bool
+	}{
+		{sql: "SELECT id FROM users", want: true},
+		{sql: "  select id FROM users", want: true},
+		{sql: "\n\tSELECT 1", want: true},
+		{sql: "INSERT INTO users DEFAULT VALUES"},
+		{sql: "UPDATE users SET name = $1"},
+		{sql: "WITH recent AS (SELECT id FROM users) SELECT * FROM recent", want: true},
+		{sql: "with recursive tree AS (SELECT 1) SELECT * FROM tree", want: true},
+		{sql: "SELECT id FROM users ORDER BY updated_at", want: true},
+		{sql: "SELECT id FROM users WHERE id = $1 FOR UPDATE"},
+		{sql: "SELECT id FROM users WHERE id = $1 for no key update SKIP LOCKED"},
+		{sql: "SELECT id FROM users FOR SHARE"},
+		{sql: "SELECT id FROM users FOR KEY SHARE NOWAIT"},
+		{sql: "WITH locked AS (SELECT id FROM users FOR UPDATE) SELECT * FROM locked"},
+		{sql: "WITH moved AS (DELETE FROM a RETURNING *) INSERT INTO b SELECT * FROM moved"},
+		{sql: "WITH changed AS (UPDATE users SET name = $1 RETURNING id) SELECT * FROM changed"},
+		{sql: "SELECTED"},
+		{sql: "SEL"},
+		{sql: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sql, func(t *testing.T) {
+			if got := isReadOnly(tt.sql); got != tt.want {
+				t.Errorf("isReadOnly(%q) = %v, want %v", tt.sql, got, tt.want)
+			}
+		})
+	}
+}
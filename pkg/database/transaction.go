@@ -0,0 +1,138 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go-api-starter/pkg/config"
+
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rs/zerolog"
+	"github.com/samber/do/v2"
+)
+
+// Querier is the subset of pgx shared by *pgxpool.Pool and pgx.Tx, so repositories
+// can run the same queries inside or outside a transaction.
+type Querier interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults
+	CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error)
+}
+
+type txContextKey struct{}
+
+// txFromContext returns the transaction started by TxManager, if any.
+func txFromContext(ctx context.Context) (pgx.Tx, bool) {
+	tx, ok := ctx.Value(txContextKey{}).(pgx.Tx)
+	return tx, ok
+}
+
+// InTx reports whether ctx carries a transaction.
+func InTx(ctx context.Context) bool {
+	_, ok := txFromContext(ctx)
+	return ok
+}
+
+// Querier returns the transaction carried by ctx, or the primary pool when there is none.
+// Statements that are not read-only mark the session of ctx as written, see WithSession.
+func (db *Postgresql) Querier(ctx context.Context) Querier {
+	if tx, ok := txFromContext(ctx); ok {
+		return tx
+	}
+	if s := sessionFromContext(ctx); s != nil {
+		return primaryQuerier{pool: db.pool, session: s}
+	}
+	return db.pool
+}
+
+// ReadQuerier returns a querier for reads that tolerate replication lag. It is a healthy
+// replica picked round-robin, unless ctx carries a transaction, asks for the primary with
+// WithPrimary or has written in its session; the primary also serves reads when no replica
+// is configured or healthy. Statements that lock or modify rows always go to the primary.
+func (db *Postgresql) ReadQuerier(ctx context.Context) Querier {
+	if tx, ok := txFromContext(ctx); ok {
+		return tx
+	}
+	if db.replicas == nil || readsFromPrimary(ctx) {
+		return db.pool
+	}
+	if r := db.replicas.pick(); r != nil {
+		return replicaQuerier{replica: r.pool, primary: db.Querier(ctx)}
+	}
+	return db.pool
+}
+
+// TxManager runs functions in a transaction that repositories pick up from the context
+// through Postgresql.Querier.
+type TxManager struct {
+	db         *Postgresql
+	logger     *zerolog.Logger
+	maxRetries int
+}
+
+func NewTxManager(i do.Injector) (*TxManager, error) {
+	cfg := do.MustInvoke[*config.Config](i)
+	db := do.MustInvoke[*Postgresql](i)
+	logger := do.MustInvoke[*zerolog.Logger](i)
+
+	return &TxManager{db: db, logger: logger, maxRetries: cfg.Postgresql.TxMaxRetries}, nil
+}
+
+// WithTx runs fn in a read committed transaction. See WithTxOptions.
+func (m *TxManager) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return m.WithTxOptions(ctx, pgx.TxOptions{}, fn)
+}
+
+// WithTxOptions runs fn in a transaction carried by the context passed to fn. The transaction
+// is committed when fn returns nil and rolled back otherwise.
+//
+// When ctx already carries a transaction, fn runs in a savepoint of it and opts are ignored,
+// so a failing inner call only rolls back its own work. The outermost call retries fn on
+// serialization failures and deadlocks, so fn must not have side effects outside the database.
+func (m *TxManager) WithTxOptions(ctx context.Context, opts pgx.TxOptions, fn func(ctx context.Context) error) error {
+	if tx, ok := txFromContext(ctx); ok {
+		return pgx.BeginFunc(ctx, tx, func(savepoint pgx.Tx) error {
+			return fn(context.WithValue(ctx, txContextKey{}, savepoint))
+		})
+	}
+
+	if opts.AccessMode != pgx.ReadOnly {
+		markWrite(ctx)
+	}
+
+	for attempt := 0; ; attempt++ {
+		err := pgx.BeginTxFunc(ctx, m.db.pool, opts, func(tx pgx.Tx) error {
+			return fn(context.WithValue(ctx, txContextKey{}, tx))
+		})
+		if err == nil || !isRetryable(err) {
+			return err
+		}
+		if attempt >= m.maxRetries {
+			return fmt.Errorf("transaction failed after %d attempts: %w", attempt+1, err)
+		}
+
+		backoff := time.Duration(attempt+1) * 20 * time.Millisecond
+		m.logger.Warn().Ctx(ctx).Err(err).Int("attempt", attempt+1).Dur("backoff", backoff).Msg("retrying transaction")
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+	}
+}
+
+// isRetryable reports whether err aborted the transaction because of a concurrent one.
+func isRetryable(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	return pgErr.Code == pgerrcode.SerializationFailure || pgErr.Code == pgerrcode.DeadlockDetected
+}
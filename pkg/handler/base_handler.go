@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"go-api-starter/pkg/apperrors"
 	"net/http"
 	"time"
@@ -38,8 +39,13 @@ type (
 type BaseHandler interface {
 	SuccessResponse(c echo.Context, data any, meta any, message string) error
 	BadRequest(appErrCode apperrors.ErrorCode, message string, details ...any) *echo.HTTPError
+	Unauthorized(appErrCode apperrors.ErrorCode, message string, details ...any) *echo.HTTPError
+	Forbidden(appErrCode apperrors.ErrorCode, message string, details ...any) *echo.HTTPError
 	NotFound(appErrCode apperrors.ErrorCode, message string, details ...any) *echo.HTTPError
+	Conflict(appErrCode apperrors.ErrorCode, message string, details ...any) *echo.HTTPError
+	TooManyRequests(appErrCode apperrors.ErrorCode, message string, details ...any) *echo.HTTPError
 	InternalServerError(appErrCode apperrors.ErrorCode, message string, details ...any) *echo.HTTPError
+	HandleError(err error) *echo.HTTPError
 }
 
 type baseHandler struct{}
@@ -77,10 +83,59 @@ func (h *baseHandler) BadRequest(appErrCode apperrors.ErrorCode, message string,
 	return NewErrorResponse(http.StatusBadRequest, appErrCode, message, details...)
 }
 
+func (h *baseHandler) Unauthorized(appErrCode apperrors.ErrorCode, message string, details ...any) *echo.HTTPError {
+	return NewErrorResponse(http.StatusUnauthorized, appErrCode, message, details...)
+}
+
+func (h *baseHandler) Forbidden(appErrCode apperrors.ErrorCode, message string, details ...any) *echo.HTTPError {
+	return NewErrorResponse(http.StatusForbidden, appErrCode, message, details...)
+}
+
 func (h *baseHandler) NotFound(appErrCode apperrors.ErrorCode, message string, details ...any) *echo.HTTPError {
 	return NewErrorResponse(http.StatusNotFound, appErrCode, message, details...)
 }
 
+func (h *baseHandler) Conflict(appErrCode apperrors.ErrorCode, message string, details ...any) *echo.HTTPError {
+	return NewErrorResponse(http.StatusConflict, appErrCode, message, details...)
+}
+
+func (h *baseHandler) TooManyRequests(appErrCode apperrors.ErrorCode, message string, details ...any) *echo.HTTPError {
+	return NewErrorResponse(http.StatusTooManyRequests, appErrCode, message, details...)
+}
+
 func (h *baseHandler) InternalServerError(appErrCode apperrors.ErrorCode, message string, details ...any) *echo.HTTPError {
 	return NewErrorResponse(http.StatusInternalServerError, appErrCode, message, details...)
 }
+
+// HandleError converts an error returned by a service into an HTTP error response.
+// Errors that are not an *apperrors.AppError are reported as internal server errors.
+func (h *baseHandler) HandleError(err error) *echo.HTTPError {
+	var appErr *apperrors.AppError
+	if !errors.As(err, &appErr) {
+		return h.InternalServerError(apperrors.ErrInternalServer, "internal server error")
+	}
+
+	var details []any
+	if appErr.Details != nil {
+		details = append(details, appErr.Details)
+	}
+
+	switch {
+	case appErr.Code == apperrors.ErrAccountLocked || appErr.Code == apperrors.ErrTooManyLoginAttempts || appErr.Code == apperrors.ErrTooManyOTPRequests:
+		return h.TooManyRequests(appErr.Code, appErr.Message, details...)
+	case appErr.Code == apperrors.ErrForbidden || appErr.Code == apperrors.ErrUserInactive || appErr.Code == apperrors.ErrAccountNotVerified:
+		return h.Forbidden(appErr.Code, appErr.Message, details...)
+	case appErr.Code >= 1000 && appErr.Code < 2000:
+		return h.Unauthorized(appErr.Code, appErr.Message, details...)
+	case appErr.Code >= 2000 && appErr.Code < 3000:
+		return h.BadRequest(appErr.Code, appErr.Message, details...)
+	case appErr.Code >= apperrors.ErrAlreadyExists && appErr.Code <= apperrors.ErrPermissionAlreadyExists:
+		return h.Conflict(appErr.Code, appErr.Message, details...)
+	case appErr.Code >= 3000 && appErr.Code < 4000:
+		return h.NotFound(appErr.Code, appErr.Message, details...)
+	case appErr.Code >= 4000 && appErr.Code < 5000:
+		return h.BadRequest(appErr.Code, appErr.Message, details...)
+	default:
+		return h.InternalServerError(appErr.Code, appErr.Message, details...)
+	}
+}
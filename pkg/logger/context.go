@@ -0,0 +1,40 @@
+package logger
+
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+)
+
+type requestInfoKey struct{}
+
+// RequestInfo holds the correlation fields of an HTTP request. UserID is filled in
+// once the request has been authenticated.
+type RequestInfo struct {
+	RequestID string
+	ClientIP  string
+	UserID    string
+}
+
+// WithRequest stores the request info in ctx together with a child logger bound to ctx,
+// which handlers can retrieve with FromContext.
+func WithRequest(ctx context.Context, logger *zerolog.Logger, info *RequestInfo) context.Context {
+	ctx = context.WithValue(ctx, requestInfoKey{}, info)
+	child := logger.With().Ctx(ctx).Logger()
+	return child.WithContext(ctx)
+}
+
+// RequestInfoFromContext returns the request info stored by WithRequest, or nil.
+func RequestInfoFromContext(ctx context.Context) *RequestInfo {
+	if ctx == nil {
+		return nil
+	}
+	info, _ := ctx.Value(requestInfoKey{}).(*RequestInfo)
+	return info
+}
+
+// FromContext returns the request logger stored by WithRequest. Outside of a request it
+// returns the disabled logger, so callers never need a nil check.
+func FromContext(ctx context.Context) *zerolog.Logger {
+	return zerolog.Ctx(ctx)
+}
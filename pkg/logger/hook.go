@@ -0,0 +1,27 @@
+package logger
+
+import (
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// contextHook adds the correlation fields found in the event context, so log lines written
+// with logger.Info().Ctx(ctx) can be tied to their request and trace.
+type contextHook struct{}
+
+func (contextHook) Run(e *zerolog.Event, _ zerolog.Level, _ string) {
+	ctx := e.GetCtx()
+
+	if info := RequestInfoFromContext(ctx); info != nil {
+		e.Str("request_id", info.RequestID).Str("client_ip", info.ClientIP)
+		if info.UserID != "" {
+			e.Str("user_id", info.UserID)
+		}
+	}
+
+	spanContext := trace.SpanContextFromContext(ctx)
+	if spanContext.IsValid() {
+		e.Str("trace_id", spanContext.TraceID().String()).
+			Str("span_id", spanContext.SpanID().String())
+	}
+}
@@ -1,13 +1,17 @@
 package logger
 
 import (
+	"fmt"
 	"io"
 	"os"
+	"strings"
+	"time"
 
 	"go-api-starter/pkg/config"
 
 	"github.com/rs/zerolog"
 	"github.com/samber/do/v2"
+	"gopkg.in/natefinch/lumberjack.v2"
 )
 
 // Config holds the logger configuration.
@@ -18,13 +22,22 @@ type Config struct {
 	NoColor bool
 }
 
+// Supported log formats
+const (
+	FormatConsole = "console"
+	FormatJSON    = "json"
+)
+
+const consoleTimeFormat = "2006-01-02 15:04:05"
+
 // NewLogger creates a new zerolog logger instance with dependency injection support
 // This service is automatically registered with the do dependency injection container.
 func NewLogger(i do.Injector) (*zerolog.Logger, error) {
 	appConfig := do.MustInvoke[*config.Config](i)
+	cfg := appConfig.Logger
 
 	// Configure log level
-	level, err := zerolog.ParseLevel(appConfig.Logger.Level)
+	level, err := zerolog.ParseLevel(cfg.Level)
 	if err != nil {
 		level = zerolog.InfoLevel
 	}
@@ -32,35 +45,88 @@ func NewLogger(i do.Injector) (*zerolog.Logger, error) {
 	// Set global log level
 	zerolog.SetGlobalLevel(level)
 
-	// Configure output
-	var output io.Writer
-	if appConfig.Logger.Output == "stdout" || appConfig.Logger.Output == "" {
-		output = zerolog.ConsoleWriter{
-			Out:        os.Stdout,
-			NoColor:    appConfig.Logger.NoColor,
-			TimeFormat: "2006-01-02 15:04:05",
-		}
-	} else {
-		//bearer:disable go_gosec_file_permissions_file_perm
-		file, err := os.OpenFile(appConfig.Logger.Output, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
-		if err != nil {
-			// Fall back to stdout if file creation fails
-			output = zerolog.ConsoleWriter{
-				Out:        os.Stdout,
-				NoColor:    true,
-				TimeFormat: "2006-01-02 15:04:05",
-			}
-		} else {
-			output = zerolog.MultiLevelWriter(zerolog.ConsoleWriter{
-				Out:        file,
-				NoColor:    true,
-				TimeFormat: "2006-01-02 15:04:05",
-			})
+	format := strings.ToLower(strings.TrimSpace(cfg.Format))
+	if format == "" {
+		format = FormatConsole
+	}
+	if format != FormatConsole && format != FormatJSON {
+		return nil, fmt.Errorf("unsupported log format: %s", cfg.Format)
+	}
+
+	// Output is a comma separated list of stdout, stderr or file paths,
+	// e.g. "stdout,logs/app.log" writes every line to both
+	var writers []io.Writer
+	for _, output := range strings.Split(cfg.Output, ",") {
+		output = strings.TrimSpace(output)
+		switch output {
+		case "", "stdout":
+			writers = append(writers, newWriter(os.Stdout, format, cfg.NoColor))
+		case "stderr":
+			writers = append(writers, newWriter(os.Stderr, format, cfg.NoColor))
+		default:
+			writers = append(writers, newWriter(newFileWriter(output, cfg), format, true))
 		}
 	}
 
+	output := writers[0]
+	if len(writers) > 1 {
+		output = zerolog.MultiLevelWriter(writers...)
+	}
+
 	// Create and configure logger
-	logger := zerolog.New(output).With().Timestamp().Logger()
+	logger := zerolog.New(output).With().Timestamp().Logger().Hook(contextHook{})
+
+	if cfg.SamplingBurst > 0 || cfg.SamplingRate > 0 {
+		logger = logger.Sample(newDebugSampler(cfg))
+	}
 
 	return &logger, nil
 }
+
+func newWriter(out io.Writer, format string, noColor bool) io.Writer {
+	if format == FormatJSON {
+		return out
+	}
+
+	return zerolog.ConsoleWriter{
+		Out:        out,
+		NoColor:    noColor,
+		TimeFormat: consoleTimeFormat,
+	}
+}
+
+// newFileWriter returns a file writer that rotates once the file reaches MaxSize megabytes,
+// removes rotated files older than MaxAge days and keeps at most MaxBackups of them.
+func newFileWriter(path string, cfg config.LoggerConfig) io.Writer {
+	return &lumberjack.Logger{
+		Filename:   path,
+		MaxSize:    cfg.MaxSize,
+		MaxAge:     cfg.MaxAge,
+		MaxBackups: cfg.MaxBackups,
+		Compress:   cfg.Compress,
+		LocalTime:  true,
+	}
+}
+
+// newDebugSampler samples debug and trace logs only: the first SamplingBurst lines of every
+// second are written, after that one line out of every SamplingRate.
+func newDebugSampler(cfg config.LoggerConfig) zerolog.Sampler {
+	var next zerolog.Sampler
+	if cfg.SamplingRate > 0 {
+		next = &zerolog.BasicSampler{N: uint32(cfg.SamplingRate)}
+	}
+
+	var sampler zerolog.Sampler = next
+	if cfg.SamplingBurst > 0 {
+		sampler = &zerolog.BurstSampler{
+			Burst:       uint32(cfg.SamplingBurst),
+			Period:      time.Second,
+			NextSampler: next,
+		}
+	}
+
+	return zerolog.LevelSampler{
+		TraceSampler: sampler,
+		DebugSampler: sampler,
+	}
+}
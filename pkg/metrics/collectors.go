@@ -0,0 +1,99 @@
+package metrics
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+)
+
+// pgxPoolCollector exposes pgxpool statistics, read from the pool on every scrape.
+// The pool label tells the primary from the read replicas.
+type pgxPoolCollector struct {
+	pool *pgxpool.Pool
+
+	acquiredConns     *prometheus.Desc
+	idleConns         *prometheus.Desc
+	totalConns        *prometheus.Desc
+	maxConns          *prometheus.Desc
+	acquireCount      *prometheus.Desc
+	emptyAcquireCount *prometheus.Desc
+	emptyAcquireWait  *prometheus.Desc
+	canceledAcquires  *prometheus.Desc
+}
+
+func newPgxPoolCollector(name string, pool *pgxpool.Pool) *pgxPoolCollector {
+	labels := prometheus.Labels{"pool": name}
+	return &pgxPoolCollector{
+		pool:              pool,
+		acquiredConns:     prometheus.NewDesc("pgxpool_acquired_conns", "Number of connections currently acquired from the pool.", nil, labels),
+		idleConns:         prometheus.NewDesc("pgxpool_idle_conns", "Number of idle connections in the pool.", nil, labels),
+		totalConns:        prometheus.NewDesc("pgxpool_total_conns", "Total number of connections in the pool.", nil, labels),
+		maxConns:          prometheus.NewDesc("pgxpool_max_conns", "Maximum size of the pool.", nil, labels),
+		acquireCount:      prometheus.NewDesc("pgxpool_acquire_total", "Total number of successful acquires from the pool.", nil, labels),
+		emptyAcquireCount: prometheus.NewDesc("pgxpool_empty_acquire_total", "Total number of acquires that had to wait for a connection.", nil, labels),
+		emptyAcquireWait:  prometheus.NewDesc("pgxpool_empty_acquire_wait_seconds_total", "Total time spent waiting for a connection.", nil, labels),
+		canceledAcquires:  prometheus.NewDesc("pgxpool_canceled_acquire_total", "Total number of acquires canceled by a context.", nil, labels),
+	}
+}
+
+func (c *pgxPoolCollector) Describe(ch chan<- *prometheus.Desc) {
+	prometheus.DescribeByCollect(c, ch)
+}
+
+func (c *pgxPoolCollector) Collect(ch chan<- prometheus.Metric) {
+	stat := c.pool.Stat()
+
+	ch <- prometheus.MustNewConstMetric(c.acquiredConns, prometheus.GaugeValue, float64(stat.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(c.idleConns, prometheus.GaugeValue, float64(stat.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(c.totalConns, prometheus.GaugeValue, float64(stat.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(c.maxConns, prometheus.GaugeValue, float64(stat.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(c.acquireCount, prometheus.CounterValue, float64(stat.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.emptyAcquireCount, prometheus.CounterValue, float64(stat.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.emptyAcquireWait, prometheus.CounterValue, stat.EmptyAcquireWaitTime().Seconds())
+	ch <- prometheus.MustNewConstMetric(c.canceledAcquires, prometheus.CounterValue, float64(stat.CanceledAcquireCount()))
+}
+
+// redisPoolCollector exposes the connection pool statistics of the go-redis client.
+type redisPoolCollector struct {
+	client *redis.Client
+
+	hits       *prometheus.Desc
+	misses     *prometheus.Desc
+	timeouts   *prometheus.Desc
+	waitCount  *prometheus.Desc
+	waitTime   *prometheus.Desc
+	totalConns *prometheus.Desc
+	idleConns  *prometheus.Desc
+	staleConns *prometheus.Desc
+}
+
+func newRedisPoolCollector(client *redis.Client) *redisPoolCollector {
+	return &redisPoolCollector{
+		client:     client,
+		hits:       prometheus.NewDesc("redis_pool_hits_total", "Number of times a free connection was found in the pool.", nil, nil),
+		misses:     prometheus.NewDesc("redis_pool_misses_total", "Number of times a free connection was not found in the pool.", nil, nil),
+		timeouts:   prometheus.NewDesc("redis_pool_timeouts_total", "Number of times a wait for a connection timed out.", nil, nil),
+		waitCount:  prometheus.NewDesc("redis_pool_wait_total", "Number of times a connection was waited for.", nil, nil),
+		waitTime:   prometheus.NewDesc("redis_pool_wait_seconds_total", "Total time spent waiting for a connection.", nil, nil),
+		totalConns: prometheus.NewDesc("redis_pool_total_conns", "Total number of connections in the pool.", nil, nil),
+		idleConns:  prometheus.NewDesc("redis_pool_idle_conns", "Number of idle connections in the pool.", nil, nil),
+		staleConns: prometheus.NewDesc("redis_pool_stale_conns_total", "Number of stale connections removed from the pool.", nil, nil),
+	}
+}
+
+func (c *redisPoolCollector) Describe(ch chan<- *prometheus.Desc) {
+	prometheus.DescribeByCollect(c, ch)
+}
+
+func (c *redisPoolCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.client.PoolStats()
+
+	ch <- prometheus.MustNewConstMetric(c.hits, prometheus.CounterValue, float64(stats.Hits))
+	ch <- prometheus.MustNewConstMetric(c.misses, prometheus.CounterValue, float64(stats.Misses))
+	ch <- prometheus.MustNewConstMetric(c.timeouts, prometheus.CounterValue, float64(stats.Timeouts))
+	ch <- prometheus.MustNewConstMetric(c.waitCount, prometheus.CounterValue, float64(stats.WaitCount))
+	ch <- prometheus.MustNewConstMetric(c.waitTime, prometheus.CounterValue, float64(stats.WaitDurationNs)/1e9)
+	ch <- prometheus.MustNewConstMetric(c.totalConns, prometheus.GaugeValue, float64(stats.TotalConns))
+	ch <- prometheus.MustNewConstMetric(c.idleConns, prometheus.GaugeValue, float64(stats.IdleConns))
+	ch <- prometheus.MustNewConstMetric(c.staleConns, prometheus.CounterValue, float64(stats.StaleConns))
+}
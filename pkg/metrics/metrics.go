@@ -0,0 +1,115 @@
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go-api-starter/pkg/cache"
+	"go-api-starter/pkg/database"
+
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/samber/do/v2"
+)
+
+// unmatchedRoute labels requests that did not match any registered route,
+// so that scanners probing random URLs cannot blow up the label cardinality.
+const unmatchedRoute = "unmatched"
+
+// Metrics owns the Prometheus registry of the service and the HTTP request instruments.
+type Metrics struct {
+	Registry        *prometheus.Registry
+	requestsTotal   *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+}
+
+func NewMetrics(i do.Injector) (*Metrics, error) {
+	postgresql := do.MustInvoke[*database.Postgresql](i)
+	redis := do.MustInvoke[*cache.Redis](i)
+
+	m := &Metrics{
+		Registry: prometheus.NewRegistry(),
+		requestsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "Total number of HTTP requests by route, method and status.",
+		}, []string{"route", "method", "status"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "HTTP request latency by route, method and status.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"route", "method", "status"}),
+	}
+
+	if err := registerAll(m.Registry,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requestsTotal,
+		m.requestDuration,
+		newPgxPoolCollector("primary", postgresql.Pool()),
+		newRedisPoolCollector(redis.Client()),
+	); err != nil {
+		return nil, err
+	}
+	for name, pool := range postgresql.ReplicaPools() {
+		if err := m.Registry.Register(newPgxPoolCollector(name, pool)); err != nil {
+			return nil, err
+		}
+	}
+
+	return m, nil
+}
+
+func registerAll(registry *prometheus.Registry, cs ...prometheus.Collector) error {
+	for _, c := range cs {
+		if err := registry.Register(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Handler serves the registry in the Prometheus exposition format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.Registry, promhttp.HandlerOpts{Registry: m.Registry})
+}
+
+// Middleware records a counter and a latency histogram for every request, labelled by the
+// route template (e.g. /api/v1/auth/admin/users/:id) rather than the raw path.
+func (m *Metrics) Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			err := next(c)
+
+			route := c.Path()
+			if route == "" {
+				route = unmatchedRoute
+			}
+
+			status := c.Response().Status
+			if err != nil && !c.Response().Committed {
+				// The error has not been rendered yet, so derive the status the error handler will use
+				var httpErr *echo.HTTPError
+				if errors.As(err, &httpErr) {
+					status = httpErr.Code
+				} else {
+					status = http.StatusInternalServerError
+				}
+			}
+
+			labels := prometheus.Labels{
+				"route":  route,
+				"method": c.Request().Method,
+				"status": strconv.Itoa(status),
+			}
+			m.requestsTotal.With(labels).Inc()
+			m.requestDuration.With(labels).Observe(time.Since(start).Seconds())
+
+			return err
+		}
+	}
+}
@@ -1 +1,77 @@
 package middleware
+
+import (
+	"errors"
+	"strings"
+
+	"go-api-starter/pkg/apperrors"
+	"go-api-starter/pkg/constants"
+	"go-api-starter/pkg/handler"
+	"go-api-starter/pkg/logger"
+	"go-api-starter/pkg/utils"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+var baseHandler = handler.NewBaseHandler()
+
+// RequireAuth verifies the bearer access token of the request and stores its claims
+// under constants.ContextTokenData. Requests without a valid token are rejected with 401.
+func RequireAuth(tokenService *utils.TokenService) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			token, ok := BearerToken(c)
+			if !ok {
+				return baseHandler.Unauthorized(apperrors.ErrUnauthorized, "missing bearer token")
+			}
+
+			claims, err := tokenService.VerifyToken(c.Request().Context(), token, constants.ScopeTokenAccess)
+			if err != nil {
+				switch {
+				case errors.Is(err, utils.ErrExpiredToken):
+					return baseHandler.Unauthorized(apperrors.ErrTokenExpired, "access token has expired")
+				case errors.Is(err, utils.ErrInvalidToken), errors.Is(err, utils.ErrInvalidTokenScope), errors.Is(err, utils.ErrRevokedToken):
+					return baseHandler.Unauthorized(apperrors.ErrInvalidToken, "invalid access token")
+				default:
+					return baseHandler.InternalServerError(apperrors.ErrInternalServer, "failed to validate access token")
+				}
+			}
+
+			c.Set(constants.ContextTokenData, claims)
+			if info := logger.RequestInfoFromContext(c.Request().Context()); info != nil {
+				info.UserID = claims.Subject
+			}
+			return next(c)
+		}
+	}
+}
+
+// BearerToken returns the token from the Authorization header of the request.
+func BearerToken(c echo.Context) (string, bool) {
+	token, found := strings.CutPrefix(c.Request().Header.Get(echo.HeaderAuthorization), "Bearer ")
+	if !found || token == "" {
+		return "", false
+	}
+	return token, true
+}
+
+// GetTokenClaims returns the claims stored by RequireAuth.
+func GetTokenClaims(c echo.Context) (*utils.TokenClaims, bool) {
+	claims, ok := c.Get(constants.ContextTokenData).(*utils.TokenClaims)
+	return claims, ok
+}
+
+// GetUserID returns the id of the user authenticated by RequireAuth.
+func GetUserID(c echo.Context) (uuid.UUID, bool) {
+	claims, ok := GetTokenClaims(c)
+	if !ok {
+		return uuid.Nil, false
+	}
+
+	userID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return uuid.Nil, false
+	}
+	return userID, true
+}
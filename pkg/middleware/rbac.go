@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"context"
+	"slices"
+
+	"go-api-starter/pkg/apperrors"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// AccessResolver resolves the roles and effective permissions of a user.
+type AccessResolver interface {
+	GetUserRoles(ctx context.Context, userID uuid.UUID) ([]string, error)
+	GetUserPermissions(ctx context.Context, userID uuid.UUID) ([]string, error)
+}
+
+// RequirePermission allows the request only when the authenticated user holds every given permission.
+// It must run after RequireAuth.
+func RequirePermission(resolver AccessResolver, permissions ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			userID, ok := GetUserID(c)
+			if !ok {
+				return baseHandler.Unauthorized(apperrors.ErrUnauthorized, "unauthorized")
+			}
+
+			granted, err := resolver.GetUserPermissions(c.Request().Context(), userID)
+			if err != nil {
+				return baseHandler.HandleError(err)
+			}
+
+			for _, permission := range permissions {
+				if !slices.Contains(granted, permission) {
+					return baseHandler.Forbidden(apperrors.ErrForbidden, "permission denied")
+				}
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// RequireRole allows the request when the authenticated user holds at least one of the given roles.
+// It must run after RequireAuth.
+func RequireRole(resolver AccessResolver, roles ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			userID, ok := GetUserID(c)
+			if !ok {
+				return baseHandler.Unauthorized(apperrors.ErrUnauthorized, "unauthorized")
+			}
+
+			assigned, err := resolver.GetUserRoles(c.Request().Context(), userID)
+			if err != nil {
+				return baseHandler.HandleError(err)
+			}
+
+			for _, role := range roles {
+				if slices.Contains(assigned, role) {
+					return next(c)
+				}
+			}
+
+			return baseHandler.Forbidden(apperrors.ErrForbidden, "permission denied")
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"go-api-starter/pkg/constants"
+	"go-api-starter/pkg/database"
+	"go-api-starter/pkg/logger"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
+)
+
+// readYourWritesCookie holds a random ID naming the client's read-your-writes window in Redis.
+const readYourWritesCookie = "db_primary_reads"
+
+// ReadYourWrites starts a database session per request, so reads made after a write in the same
+// request are served by the primary. With a window it also carries this across requests: when a
+// request writes to the primary, the window is stored in Redis under a random ID sent back in a
+// cookie, and requests presenting that ID read from the primary until the window ends, so they are
+// not served by a replica that has not caught up yet. The window is kept server-side, so a client
+// cannot forge or extend it to pin its reads to the primary.
+func ReadYourWrites(window time.Duration, redisClient *redis.Client) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx := database.WithSession(c.Request().Context())
+			c.SetRequest(c.Request().WithContext(ctx))
+			if window <= 0 {
+				return next(c)
+			}
+
+			if cookie, err := c.Cookie(readYourWritesCookie); err == nil {
+				if until := primaryReadsUntil(ctx, redisClient, cookie.Value, window); !until.IsZero() {
+					database.ReadFromPrimaryUntil(ctx, until)
+				}
+			}
+
+			c.Response().Before(func() {
+				if !database.Wrote(ctx) {
+					return
+				}
+				id, err := startPrimaryReads(ctx, redisClient, window)
+				if err != nil {
+					logger.FromContext(ctx).Warn().Err(err).Msg("Failed to store the read-your-writes window")
+					return
+				}
+				c.SetCookie(&http.Cookie{
+					Name:     readYourWritesCookie,
+					Value:    id,
+					Path:     "/",
+					Expires:  time.Now().Add(window),
+					HttpOnly: true,
+					SameSite: http.SameSiteLaxMode,
+				})
+			})
+
+			return next(c)
+		}
+	}
+}
+
+// startPrimaryReads stores a window of reads from the primary under a new ID and returns the ID.
+func startPrimaryReads(ctx context.Context, redisClient *redis.Client, window time.Duration) (string, error) {
+	id := uuid.NewString()
+	if err := redisClient.Set(ctx, constants.RedisKeyPrimaryReads+id, 1, window).Err(); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// primaryReadsUntil returns when the window stored under id ends, or the zero time when there is none.
+// If Redis cannot be reached the reads go to the primary for a whole window, trading load on the
+// primary for not serving stale data.
+func primaryReadsUntil(ctx context.Context, redisClient *redis.Client, id string, window time.Duration) time.Time {
+	if uuid.Validate(id) != nil {
+		return time.Time{}
+	}
+
+	ttl, err := redisClient.PTTL(ctx, constants.RedisKeyPrimaryReads+id).Result()
+	if err != nil {
+		logger.FromContext(ctx).Warn().Err(err).Msg("Failed to read the read-your-writes window")
+		return time.Now().Add(window)
+	}
+	if ttl <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(min(ttl, window))
+}
@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-api-starter/pkg/constants"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestPrimaryReadsUntil(t *testing.T) {
+	ctx := context.Background()
+	window := 5 * time.Second
+
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	id, err := startPrimaryReads(ctx, client, window)
+	if err != nil {
+		t.Fatalf("startPrimaryReads: %v", err)
+	}
+	if ttl := server.TTL(constants.RedisKeyPrimaryReads + id); ttl != window {
+		t.Fatalf("window ttl = %s, want %s", ttl, window)
+	}
+
+	until := primaryReadsUntil(ctx, client, id, window)
+	if remaining := time.Until(until); remaining <= 0 || remaining > window {
+		t.Fatalf("stored window ends in %s, want within %s", remaining, window)
+	}
+
+	// Clients cannot make up a window the server did not start
+	for name, forged := range map[string]string{
+		"unknown id": uuid.NewString(),
+		"timestamp":  "99999999999999",
+		"empty":      "",
+	} {
+		t.Run(name, func(t *testing.T) {
+			if until := primaryReadsUntil(ctx, client, forged, window); !until.IsZero() {
+				t.Fatalf("forged cookie %q reads from the primary until %s", forged, until)
+			}
+		})
+	}
+
+	server.FastForward(window)
+	if until := primaryReadsUntil(ctx, client, id, window); !until.IsZero() {
+		t.Fatalf("expired window reads from the primary until %s", until)
+	}
+
+	// Without Redis a known client keeps reading its writes
+	id, err = startPrimaryReads(ctx, client, window)
+	if err != nil {
+		t.Fatalf("startPrimaryReads: %v", err)
+	}
+	server.Close()
+	if until := primaryReadsUntil(ctx, client, id, window); time.Until(until) <= 0 {
+		t.Fatal("reads left the primary while Redis was unavailable")
+	}
+}
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"go-api-starter/pkg/logger"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+)
+
+// maxRequestIDLength bounds client supplied request IDs so they cannot flood the logs.
+const maxRequestIDLength = 128
+
+// RequestContext honors the X-Request-ID header of the request or generates a new one,
+// echoes it in the response and attaches a child logger carrying the request ID and client IP
+// to the request context.
+func RequestContext(baseLogger *zerolog.Logger) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			requestID := c.Request().Header.Get(echo.HeaderXRequestID)
+			if !validRequestID(requestID) {
+				requestID = uuid.NewString()
+			}
+			c.Response().Header().Set(echo.HeaderXRequestID, requestID)
+
+			info := &logger.RequestInfo{
+				RequestID: requestID,
+				ClientIP:  c.RealIP(),
+			}
+			ctx := logger.WithRequest(c.Request().Context(), baseLogger, info)
+			c.SetRequest(c.Request().WithContext(ctx))
+
+			return next(c)
+		}
+	}
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		if r < 0x21 || r > 0x7e {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+)
+
+func TestValidRequestID(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		want bool
+	}{
+		{name: "uuid", id: "0b7c8f0e-5f4a-4e43-9a57-3c1f0d2b8e11", want: true},
+		{name: "printable ascii", id: "req_123:abc/XYZ~!", want: true},
+		{name: "max length", id: strings.Repeat("a", maxRequestIDLength), want: true},
+		{name: "empty"},
+		{name: "too long", id: strings.Repeat("a", maxRequestIDLength+1)},
+		{name: "space", id: "req 123"},
+		{name: "newline", id: "req\n123"},
+		{name: "control character", id: "req\x00123"},
+		{name: "delete", id: "req\x7f"},
+		{name: "non ascii", id: "req-é"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := validRequestID(tt.id); got != tt.want {
+				t.Errorf("validRequestID(%q) = %v, want %v", tt.id, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequestContextRequestID(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		wantEcho bool
+	}{
+		{name: "valid header is echoed", header: "req-123", wantEcho: true},
+		{name: "missing header"},
+		{name: "invalid header is replaced", header: "req 123"},
+	}
+
+	logger := zerolog.Nop()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			e.Use(RequestContext(&logger))
+			e.GET("/", func(c echo.Context) error { return c.NoContent(http.StatusNoContent) })
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set(echo.HeaderXRequestID, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			got := rec.Header().Get(echo.HeaderXRequestID)
+			if tt.wantEcho && got != tt.header {
+				t.Errorf("X-Request-ID = %q, want %q", got, tt.header)
+			}
+			if !tt.wantEcho && (got == "" || got == tt.header) {
+				t.Errorf("X-Request-ID = %q, want a generated ID", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,286 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go-api-starter/pkg/constants"
+	"go-api-starter/pkg/database"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+	"github.com/samber/do/v2"
+)
+
+const createTableQuery = `
+	CREATE TABLE IF NOT EXISTS schema_migrations (
+		id         BIGSERIAL PRIMARY KEY,
+		module     VARCHAR(100) NOT NULL,
+		version    BIGINT NOT NULL,
+		name       VARCHAR(255) NOT NULL,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		CONSTRAINT schema_migrations_module_version_unique UNIQUE (module, version)
+	)`
+
+// Status describes a migration and whether it has been applied.
+type Status struct {
+	Module    string
+	Version   int64
+	Name      string
+	AppliedAt *time.Time
+	// Missing is set for applied migrations whose files no longer exist
+	Missing bool
+}
+
+type appliedMigration struct {
+	module    string
+	version   int64
+	name      string
+	appliedAt time.Time
+}
+
+// Migrator applies the migrations of every registered module to Postgres. Runs are
+// serialized across instances with an advisory lock, so replicas starting together
+// with auto-migrate enabled do not race.
+type Migrator struct {
+	logger  *zerolog.Logger
+	pool    *pgxpool.Pool
+	sources []Source
+}
+
+func NewMigrator(i do.Injector) (*Migrator, error) {
+	return &Migrator{
+		logger:  do.MustInvoke[*zerolog.Logger](i),
+		pool:    do.MustInvoke[*database.Postgresql](i).Pool(),
+		sources: do.MustInvoke[[]Source](i),
+	}, nil
+}
+
+// Up applies every pending migration, module by module in registration order, and
+// returns how many were applied.
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	applied := 0
+	err := m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		done, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, source := range m.sources {
+			migrations, err := source.load()
+			if err != nil {
+				return err
+			}
+
+			for _, migration := range migrations {
+				if _, ok := done[migrationKey(migration.Module, migration.Version)]; ok {
+					continue
+				}
+				if err := m.run(ctx, conn, migration, true); err != nil {
+					return err
+				}
+				applied++
+			}
+		}
+		return nil
+	})
+
+	return applied, err
+}
+
+// Down rolls back the last steps applied migrations, most recent first, and returns
+// how many were rolled back.
+func (m *Migrator) Down(ctx context.Context, steps int) (int, error) {
+	rolledBack := 0
+	err := m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		rows, err := conn.Query(ctx, `SELECT module, version FROM schema_migrations ORDER BY id DESC LIMIT $1`, steps)
+		if err != nil {
+			return fmt.Errorf("failed to list applied migrations: %w", err)
+		}
+		type record struct {
+			module  string
+			version int64
+		}
+		records, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (record, error) {
+			var r record
+			err := row.Scan(&r.module, &r.version)
+			return r, err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to list applied migrations: %w", err)
+		}
+
+		for _, r := range records {
+			migration, err := m.find(r.module, r.version)
+			if err != nil {
+				return err
+			}
+			if err := m.run(ctx, conn, migration, false); err != nil {
+				return err
+			}
+			rolledBack++
+		}
+		return nil
+	})
+
+	return rolledBack, err
+}
+
+// Status lists the known migrations of every module followed by applied migrations
+// whose files are missing.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	var statuses []Status
+	err := m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		done, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, source := range m.sources {
+			migrations, err := source.load()
+			if err != nil {
+				return err
+			}
+			for _, migration := range migrations {
+				status := Status{Module: migration.Module, Version: migration.Version, Name: migration.Name}
+				key := migrationKey(migration.Module, migration.Version)
+				if record, ok := done[key]; ok {
+					status.AppliedAt = &record.appliedAt
+					delete(done, key)
+				}
+				statuses = append(statuses, status)
+			}
+		}
+
+		for _, record := range done {
+			appliedAt := record.appliedAt
+			statuses = append(statuses, Status{
+				Module:    record.module,
+				Version:   record.version,
+				Name:      record.name,
+				AppliedAt: &appliedAt,
+				Missing:   true,
+			})
+		}
+		return nil
+	})
+
+	return statuses, err
+}
+
+// withLock runs fn on a dedicated connection holding the migration advisory lock.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *pgxpool.Conn) error) error {
+	conn, err := m.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, `SELECT pg_advisory_lock($1)`, constants.MigrationAdvisoryLockID); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	defer func() {
+		// The lock is released with the session anyway if this fails
+		if _, err := conn.Exec(context.Background(), `SELECT pg_advisory_unlock($1)`, constants.MigrationAdvisoryLockID); err != nil {
+			m.logger.Warn().Err(err).Msg("failed to release migration lock")
+		}
+	}()
+
+	if _, err := conn.Exec(ctx, createTableQuery); err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	return fn(conn)
+}
+
+func (m *Migrator) applied(ctx context.Context, conn *pgxpool.Conn) (map[string]appliedMigration, error) {
+	rows, err := conn.Query(ctx, `SELECT module, version, name, applied_at FROM schema_migrations ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	done := make(map[string]appliedMigration)
+	for rows.Next() {
+		var record appliedMigration
+		if err := rows.Scan(&record.module, &record.version, &record.name, &record.appliedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan applied migration: %w", err)
+		}
+		done[migrationKey(record.module, record.version)] = record
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list applied migrations: %w", err)
+	}
+
+	return done, nil
+}
+
+// run applies or rolls back one migration and updates schema_migrations in the same transaction.
+func (m *Migrator) run(ctx context.Context, conn *pgxpool.Conn, migration *Migration, up bool) error {
+	label := fmt.Sprintf("%s/%06d_%s", migration.Module, migration.Version, migration.Name)
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin migration %s: %w", label, err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if up {
+		if _, err := tx.Exec(ctx, migration.Up); err != nil {
+			return fmt.Errorf("failed to apply migration %s: %w", label, err)
+		}
+		if _, err := tx.Exec(ctx,
+			`INSERT INTO schema_migrations (module, version, name) VALUES ($1, $2, $3)`,
+			migration.Module, migration.Version, migration.Name,
+		); err != nil {
+			return fmt.Errorf("failed to record migration %s: %w", label, err)
+		}
+	} else {
+		if _, err := tx.Exec(ctx, migration.Down); err != nil {
+			return fmt.Errorf("failed to roll back migration %s: %w", label, err)
+		}
+		if _, err := tx.Exec(ctx,
+			`DELETE FROM schema_migrations WHERE module = $1 AND version = $2`,
+			migration.Module, migration.Version,
+		); err != nil {
+			return fmt.Errorf("failed to record rollback of %s: %w", label, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit migration %s: %w", label, err)
+	}
+
+	if up {
+		m.logger.Info().Str("migration", label).Msg("Applied migration")
+	} else {
+		m.logger.Info().Str("migration", label).Msg("Rolled back migration")
+	}
+	return nil
+}
+
+func (m *Migrator) find(module string, version int64) (*Migration, error) {
+	source, err := findSource(m.sources, module)
+	if err != nil {
+		return nil, err
+	}
+	migrations, err := source.load()
+	if err != nil {
+		return nil, err
+	}
+	for _, migration := range migrations {
+		if migration.Version == version {
+			if migration.Down == "" {
+				return nil, fmt.Errorf("%s migration %d has no down file", module, version)
+			}
+			return migration, nil
+		}
+	}
+	return nil, fmt.Errorf("%s migration %d is applied but its files are missing", module, version)
+}
+
+func migrationKey(module string, version int64) string {
+	return fmt.Sprintf("%s/%d", module, version)
+}
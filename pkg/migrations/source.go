@@ -0,0 +1,145 @@
+package migrations
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Source is the set of migrations of one module. Versions are numbered per module,
+// so two modules can both have a 000001 migration.
+type Source struct {
+	Module string
+	// Dir is the directory of the SQL files relative to the repository root, used by Create
+	Dir string
+	// FS holds the embedded SQL files, named <version>_<name>.up.sql and <version>_<name>.down.sql
+	FS fs.FS
+}
+
+// Migration is a single versioned schema change.
+type Migration struct {
+	Module  string
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+var fileNamePattern = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// load reads and sorts the migrations of the source by version.
+func (s Source) load() ([]*Migration, error) {
+	entries, err := fs.ReadDir(s.FS, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s migrations: %w", s.Module, err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	hasUp := make(map[int64]bool)
+	for _, entry := range entries {
+		match := fileNamePattern.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+
+		version, _ := strconv.ParseInt(match[1], 10, 64)
+		content, err := fs.ReadFile(s.FS, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", entry.Name(), err)
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Module: s.Module, Version: version, Name: match[2]}
+			byVersion[version] = migration
+		} else if migration.Name != match[2] {
+			return nil, fmt.Errorf("%s migration %d has two names: %s and %s", s.Module, version, migration.Name, match[2])
+		}
+
+		if match[3] == "up" {
+			migration.Up = string(content)
+			hasUp[version] = true
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]*Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if !hasUp[migration.Version] {
+			return nil, fmt.Errorf("%s migration %d_%s has no up file", s.Module, migration.Version, migration.Name)
+		}
+		migrations = append(migrations, migration)
+	}
+	sort.Slice(migrations, func(a, b int) bool { return migrations[a].Version < migrations[b].Version })
+
+	return migrations, nil
+}
+
+var nonAlphanumeric = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Create writes an empty up and down file with the next version into the directory of
+// the module and returns their paths.
+func Create(sources []Source, module string, name string) ([]string, error) {
+	source, err := findSource(sources, module)
+	if err != nil {
+		return nil, err
+	}
+
+	name = strings.Trim(nonAlphanumeric.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if name == "" {
+		return nil, fmt.Errorf("migration name is required")
+	}
+
+	// Read the directory rather than the embedded files, which miss migrations created since the last build
+	onDisk := Source{Module: source.Module, Dir: source.Dir, FS: os.DirFS(source.Dir)}
+	migrations, err := onDisk.load()
+	if err != nil {
+		return nil, err
+	}
+	var version int64 = 1
+	if len(migrations) > 0 {
+		version = migrations[len(migrations)-1].Version + 1
+	}
+
+	var paths []string
+	for _, direction := range []string{"up", "down"} {
+		path := filepath.Join(source.Dir, fmt.Sprintf("%06d_%s.%s.sql", version, name, direction))
+		//bearer:disable go_gosec_file_permissions_file_perm
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if err != nil {
+			return paths, fmt.Errorf("failed to create migration file: %w", err)
+		}
+		_, err = fmt.Fprintf(file, "-- %s migration %06d_%s (%s)\n", source.Module, version, name, direction)
+		_ = file.Close()
+		if err != nil {
+			return paths, fmt.Errorf("failed to write migration file: %w", err)
+		}
+		paths = append(paths, path)
+	}
+
+	return paths, nil
+}
+
+// findSource returns the source of the module. The module may be omitted when only one is registered.
+func findSource(sources []Source, module string) (Source, error) {
+	if module == "" && len(sources) == 1 {
+		return sources[0], nil
+	}
+	for _, source := range sources {
+		if source.Module == module {
+			return source, nil
+		}
+	}
+
+	modules := make([]string, 0, len(sources))
+	for _, source := range sources {
+		modules = append(modules, source.Module)
+	}
+	return Source{}, fmt.Errorf("unknown migration module %q, expected one of: %s", module, strings.Join(modules, ", "))
+}
@@ -0,0 +1,97 @@
+package migrations
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestSourceLoad(t *testing.T) {
+	tests := []struct {
+		name         string
+		files        fstest.MapFS
+		wantVersions []int64
+		wantErr      bool
+	}{
+		{
+			name: "sorted by version",
+			files: fstest.MapFS{
+				"000010_add_index.up.sql":      {Data: []byte("CREATE INDEX")},
+				"000002_create_users.up.sql":   {Data: []byte("CREATE TABLE")},
+				"000002_create_users.down.sql": {Data: []byte("DROP TABLE")},
+			},
+			wantVersions: []int64{2, 10},
+		},
+		{
+			name: "other files are ignored",
+			files: fstest.MapFS{
+				"000001_init.up.sql":       {Data: []byte("SELECT 1")},
+				"README.md":                {Data: []byte("docs")},
+				"000002_Bad_Name.up.sql":   {Data: []byte("SELECT 2")},
+				"seeds/000003_seed.up.sql": {Data: []byte("SELECT 3")},
+				"000004_no_direction.sql":  {Data: []byte("SELECT 4")},
+			},
+			wantVersions: []int64{1},
+		},
+		{
+			name:         "empty",
+			files:        fstest.MapFS{},
+			wantVersions: []int64{},
+		},
+		{
+			name: "down without up",
+			files: fstest.MapFS{
+				"000001_init.down.sql": {Data: []byte("DROP TABLE")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "one version with two names",
+			files: fstest.MapFS{
+				"000001_init.up.sql":    {Data: []byte("CREATE TABLE")},
+				"000001_other.down.sql": {Data: []byte("DROP TABLE")},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			migrations, err := Source{Module: "test", FS: tt.files}.load()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if len(migrations) != len(tt.wantVersions) {
+				t.Fatalf("got %d migrations, want %d", len(migrations), len(tt.wantVersions))
+			}
+			for i, migration := range migrations {
+				if migration.Version != tt.wantVersions[i] {
+					t.Errorf("migrations[%d].Version = %d, want %d", i, migration.Version, tt.wantVersions[i])
+				}
+				if migration.Module != "test" {
+					t.Errorf("migrations[%d].Module = %q, want test", i, migration.Module)
+				}
+			}
+		})
+	}
+}
+
+func TestSourceLoadContent(t *testing.T) {
+	files := fstest.MapFS{
+		"000001_create_users.up.sql":   {Data: []byte("CREATE TABLE users ()")},
+		"000001_create_users.down.sql": {Data: []byte("DROP TABLE users")},
+	}
+
+	migrations, err := Source{Module: "auth", FS: files}.load()
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+
+	want := Migration{Module: "auth", Version: 1, Name: "create_users", Up: "CREATE TABLE users ()", Down: "DROP TABLE users"}
+	if len(migrations) != 1 || *migrations[0] != want {
+		t.Fatalf("got %+v, want [%+v]", migrations, want)
+	}
+}
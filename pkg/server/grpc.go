@@ -1 +1,280 @@
 package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go-api-starter/pkg/apperrors"
+	"go-api-starter/pkg/config"
+	"go-api-starter/pkg/constants"
+	"go-api-starter/pkg/utils"
+
+	"github.com/rs/zerolog"
+	"github.com/samber/do/v2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+// Methods of the standard services that never require a token
+var defaultPublicMethodPrefixes = []string{
+	"/grpc.health.v1.Health/",
+	"/grpc.reflection.v1.ServerReflection/",
+	"/grpc.reflection.v1alpha.ServerReflection/",
+}
+
+type tokenClaimsKey struct{}
+
+type GRPCServer struct {
+	config       *config.Config
+	logger       *zerolog.Logger
+	tokenService *utils.TokenService
+	Server       *grpc.Server
+	Health       *health.Server
+
+	mu            sync.RWMutex
+	publicMethods map[string]bool
+}
+
+func NewGRPCServer(injector do.Injector) (*GRPCServer, error) {
+	server := &GRPCServer{
+		config:        do.MustInvoke[*config.Config](injector),
+		logger:        do.MustInvoke[*zerolog.Logger](injector),
+		tokenService:  do.MustInvoke[*utils.TokenService](injector),
+		publicMethods: make(map[string]bool),
+	}
+
+	// Recovery runs first so that panics in the other interceptors are caught as well
+	server.Server = grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			server.recoveryUnaryInterceptor,
+			server.loggingUnaryInterceptor,
+			server.authUnaryInterceptor,
+		),
+		grpc.ChainStreamInterceptor(
+			server.recoveryStreamInterceptor,
+			server.loggingStreamInterceptor,
+			server.authStreamInterceptor,
+		),
+	)
+
+	server.Health = health.NewServer()
+	healthpb.RegisterHealthServer(server.Server, server.Health)
+	// Reflection lets any caller list and describe every method, so it is opt-in outside debug mode
+	if server.config.App.Debug || server.config.Server.GRPCReflection {
+		reflection.Register(server.Server)
+	}
+
+	return server, nil
+}
+
+// RegisterPublicMethods marks full method names, e.g. "/auth.v1.AuthService/ValidateToken",
+// as callable without a bearer token.
+func (s *GRPCServer) RegisterPublicMethods(methods ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, method := range methods {
+		s.publicMethods[method] = true
+	}
+}
+
+func (s *GRPCServer) Start() error {
+	address := s.config.Server.GRPCHost + ":" + strconv.Itoa(s.config.Server.GRPCPort)
+
+	s.logger.Info().
+		Str("host", s.config.Server.GRPCHost).
+		Int("port", s.config.Server.GRPCPort).
+		Msg("Starting gRPC server")
+
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", address, err)
+	}
+
+	s.Health.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	return s.Server.Serve(listener)
+}
+
+// Shutdown stops accepting new calls and waits for running ones until ctx is done,
+// after which remaining calls are cancelled.
+func (s *GRPCServer) Shutdown(ctx context.Context) error {
+	s.Health.Shutdown()
+
+	stopped := make(chan struct{})
+	go func() {
+		s.Server.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		s.Server.Stop()
+		return ctx.Err()
+	}
+}
+
+// TokenClaimsFromContext returns the claims of the access token that authenticated a gRPC call.
+func TokenClaimsFromContext(ctx context.Context) (*utils.TokenClaims, bool) {
+	claims, ok := ctx.Value(tokenClaimsKey{}).(*utils.TokenClaims)
+	return claims, ok
+}
+
+// GRPCError converts an error returned by a service into a gRPC status error.
+func GRPCError(err error) error {
+	var appErr *apperrors.AppError
+	if !errors.As(err, &appErr) {
+		return status.Error(codes.Internal, "internal server error")
+	}
+
+	switch {
+	case appErr.Code == apperrors.ErrAccountLocked || appErr.Code == apperrors.ErrTooManyLoginAttempts || appErr.Code == apperrors.ErrTooManyOTPRequests:
+		return status.Error(codes.ResourceExhausted, appErr.Message)
+	case appErr.Code == apperrors.ErrForbidden || appErr.Code == apperrors.ErrUserInactive || appErr.Code == apperrors.ErrAccountNotVerified:
+		return status.Error(codes.PermissionDenied, appErr.Message)
+	case appErr.Code >= 1000 && appErr.Code < 2000:
+		return status.Error(codes.Unauthenticated, appErr.Message)
+	case appErr.Code >= 2000 && appErr.Code < 3000:
+		return status.Error(codes.InvalidArgument, appErr.Message)
+	case appErr.Code >= apperrors.ErrAlreadyExists && appErr.Code <= apperrors.ErrPermissionAlreadyExists:
+		return status.Error(codes.AlreadyExists, appErr.Message)
+	case appErr.Code >= 3000 && appErr.Code < 4000:
+		return status.Error(codes.NotFound, appErr.Message)
+	case appErr.Code >= 4000 && appErr.Code < 5000:
+		return status.Error(codes.FailedPrecondition, appErr.Message)
+	default:
+		return status.Error(codes.Internal, appErr.Message)
+	}
+}
+
+func (s *GRPCServer) isPublicMethod(fullMethod string) bool {
+	for _, prefix := range defaultPublicMethodPrefixes {
+		if strings.HasPrefix(fullMethod, prefix) {
+			return true
+		}
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.publicMethods[fullMethod]
+}
+
+// authenticate verifies the bearer access token in the call metadata and stores its claims in the context.
+func (s *GRPCServer) authenticate(ctx context.Context, fullMethod string) (context.Context, error) {
+	if s.isPublicMethod(fullMethod) {
+		return ctx, nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "missing bearer token")
+	}
+
+	token, found := strings.CutPrefix(values[0], "Bearer ")
+	if !found || token == "" {
+		return nil, status.Error(codes.Unauthenticated, "missing bearer token")
+	}
+
+	claims, err := s.tokenService.VerifyToken(ctx, token, constants.ScopeTokenAccess)
+	if err != nil {
+		switch {
+		case errors.Is(err, utils.ErrExpiredToken):
+			return nil, status.Error(codes.Unauthenticated, "access token has expired")
+		case errors.Is(err, utils.ErrInvalidToken), errors.Is(err, utils.ErrInvalidTokenScope), errors.Is(err, utils.ErrRevokedToken):
+			return nil, status.Error(codes.Unauthenticated, "invalid access token")
+		default:
+			s.logger.Error().Err(err).Str("method", fullMethod).Msg("failed to validate access token")
+			return nil, status.Error(codes.Internal, "failed to validate access token")
+		}
+	}
+
+	return context.WithValue(ctx, tokenClaimsKey{}, claims), nil
+}
+
+func (s *GRPCServer) authUnaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := s.authenticate(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (s *GRPCServer) authStreamInterceptor(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := s.authenticate(stream.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &contextServerStream{ServerStream: stream, ctx: ctx})
+}
+
+func (s *GRPCServer) loggingUnaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	s.logCall(info.FullMethod, start, err)
+	return resp, err
+}
+
+func (s *GRPCServer) loggingStreamInterceptor(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := handler(srv, stream)
+	s.logCall(info.FullMethod, start, err)
+	return err
+}
+
+func (s *GRPCServer) logCall(fullMethod string, start time.Time, err error) {
+	s.logger.Info().
+		Str("method", fullMethod).
+		Str("code", status.Code(err).String()).
+		Dur("latency", time.Since(start)).
+		Msg("gRPC request")
+}
+
+func (s *GRPCServer) recoveryUnaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = s.recovered(info.FullMethod, r)
+		}
+	}()
+	return handler(ctx, req)
+}
+
+func (s *GRPCServer) recoveryStreamInterceptor(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = s.recovered(info.FullMethod, r)
+		}
+	}()
+	return handler(srv, stream)
+}
+
+func (s *GRPCServer) recovered(fullMethod string, r any) error {
+	s.logger.Error().
+		Str("method", fullMethod).
+		Interface("panic", r).
+		Bytes("stack", debug.Stack()).
+		Msg("Recovered from panic in gRPC handler")
+	return status.Error(codes.Internal, "internal server error")
+}
+
+// contextServerStream overrides the context of a stream so handlers see values added by interceptors.
+type contextServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextServerStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,52 @@
+package server
+
+import (
+	"testing"
+
+	"go-api-starter/pkg/config"
+	"go-api-starter/pkg/utils"
+
+	"github.com/rs/zerolog"
+	"github.com/samber/do/v2"
+)
+
+func TestNewGRPCServerReflection(t *testing.T) {
+	tests := []struct {
+		name       string
+		debug      bool
+		reflection bool
+		want       bool
+	}{
+		{name: "off", want: false},
+		{name: "enabled", reflection: true, want: true},
+		{name: "debug", debug: true, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{}
+			cfg.App.Debug = tt.debug
+			cfg.Server.GRPCReflection = tt.reflection
+
+			tokenService, err := utils.NewTokenServiceWithConfig(config.JWTConfig{Algorithm: utils.TokenAlgorithmHS256, Secret: "secret"}, nil)
+			if err != nil {
+				t.Fatalf("NewTokenServiceWithConfig: %v", err)
+			}
+			logger := zerolog.Nop()
+			injector := do.New()
+			do.ProvideValue(injector, cfg)
+			do.ProvideValue(injector, &logger)
+			do.ProvideValue(injector, tokenService)
+
+			server, err := NewGRPCServer(injector)
+			if err != nil {
+				t.Fatalf("NewGRPCServer: %v", err)
+			}
+
+			_, got := server.Server.GetServiceInfo()["grpc.reflection.v1.ServerReflection"]
+			if got != tt.want {
+				t.Fatalf("reflection registered = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
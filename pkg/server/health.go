@@ -0,0 +1,140 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go-api-starter/pkg/cache"
+	"go-api-starter/pkg/constants"
+	"go-api-starter/pkg/database"
+
+	"github.com/labstack/echo/v4"
+	"github.com/samber/do/v2"
+)
+
+// Status values reported by the health endpoints
+const (
+	HealthStatusOK           = "ok"
+	HealthStatusReady        = "ready"
+	HealthStatusNotReady     = "not_ready"
+	HealthStatusShuttingDown = "shutting_down"
+	HealthStatusUp           = "up"
+	HealthStatusDown         = "down"
+)
+
+// HealthCheckFunc checks a single dependency and returns an error when it is unavailable.
+type HealthCheckFunc func(ctx context.Context) error
+
+type DependencyStatus struct {
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+type ReadinessResponse struct {
+	Status       string                      `json:"status"`
+	Dependencies map[string]DependencyStatus `json:"dependencies"`
+}
+
+// HealthHandler serves /healthz for liveness and /readyz for readiness.
+type HealthHandler struct {
+	mu           sync.RWMutex
+	checks       map[string]HealthCheckFunc
+	shuttingDown atomic.Bool
+}
+
+func NewHealthHandler(i do.Injector) (*HealthHandler, error) {
+	postgresql := do.MustInvoke[*database.Postgresql](i)
+	redis := do.MustInvoke[*cache.Redis](i)
+
+	handler := &HealthHandler{checks: make(map[string]HealthCheckFunc)}
+	handler.AddCheck("postgresql", postgresql.HealthCheckWithContext)
+	handler.AddCheck("redis", redis.HealthCheckWithContext)
+
+	return handler, nil
+}
+
+// AddCheck registers a dependency that must be available for the service to be ready,
+// e.g. a message broker or object storage client once it is enabled.
+func (h *HealthHandler) AddCheck(name string, check HealthCheckFunc) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.checks[name] = check
+}
+
+// MarkShuttingDown makes /readyz fail so load balancers stop routing new traffic here.
+func (h *HealthHandler) MarkShuttingDown() {
+	h.shuttingDown.Store(true)
+}
+
+func (h *HealthHandler) Register(e *echo.Echo) {
+	e.GET("/healthz", h.Liveness)
+	e.GET("/readyz", h.Readiness)
+}
+
+// Liveness only reports that the process is able to serve requests. Dependencies are left
+// to the readiness probe so that an outage of Postgres or Redis does not restart every pod.
+func (h *HealthHandler) Liveness(c echo.Context) error {
+	return c.JSON(http.StatusOK, map[string]string{"status": HealthStatusOK})
+}
+
+// Readiness runs every dependency check concurrently and reports each one with its latency.
+func (h *HealthHandler) Readiness(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), constants.ShortTimeout)
+	defer cancel()
+
+	h.mu.RLock()
+	checks := make(map[string]HealthCheckFunc, len(h.checks))
+	for name, check := range h.checks {
+		checks[name] = check
+	}
+	h.mu.RUnlock()
+
+	var (
+		mu  sync.Mutex
+		wg  sync.WaitGroup
+		all = true
+	)
+	response := ReadinessResponse{Dependencies: make(map[string]DependencyStatus, len(checks))}
+
+	for name, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			start := time.Now()
+			err := check(ctx)
+			status := DependencyStatus{
+				Status:    HealthStatusUp,
+				LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+			}
+			if err != nil {
+				status.Status = HealthStatusDown
+				status.Error = err.Error()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			response.Dependencies[name] = status
+			if err != nil {
+				all = false
+			}
+		}()
+	}
+	wg.Wait()
+
+	switch {
+	case h.shuttingDown.Load():
+		response.Status = HealthStatusShuttingDown
+	case !all:
+		response.Status = HealthStatusNotReady
+	default:
+		response.Status = HealthStatusReady
+		return c.JSON(http.StatusOK, response)
+	}
+
+	return c.JSON(http.StatusServiceUnavailable, response)
+}
@@ -1,43 +1,89 @@
 package server
 
 import (
+	"context"
+	"go-api-starter/pkg/cache"
 	"go-api-starter/pkg/config"
+	"go-api-starter/pkg/metrics"
+	appMiddleware "go-api-starter/pkg/middleware"
+	"go-api-starter/pkg/tracing"
+	"net"
 	"net/http"
 	"strconv"
+	"sync/atomic"
 	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	"github.com/rs/zerolog"
 	"github.com/samber/do/v2"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/labstack/echo/otelecho"
 )
 
 type HTTPServer struct {
-	config *config.Config  `do:""`
-	logger *zerolog.Logger `do:""`
-	Server *http.Server
-	Engine *echo.Echo
+	config      *config.Config  `do:""`
+	logger      *zerolog.Logger `do:""`
+	Server      *http.Server
+	Engine      *echo.Echo
+	corsOrigins atomic.Pointer[[]string]
 }
 
 func NewHTTPServer(injector do.Injector) (*HTTPServer, error) {
 	server := do.MustInvokeStruct[*HTTPServer](injector)
 
 	server.Engine = echo.New()
+	server.Engine.IPExtractor = ipExtractor(server.config.Server.TrustedProxies)
 
-	server.Engine.Use(middleware.CORS())
+	// Start a span per request, continuing the caller's traceparent header
+	do.MustInvoke[*tracing.Provider](injector)
+	server.Engine.Use(otelecho.Middleware(server.config.App.Name, otelecho.WithSkipper(func(c echo.Context) bool {
+		path := c.Request().URL.Path
+		return path == "/healthz" || path == "/readyz" || path == server.config.Metrics.Path
+	})))
+
+	server.Engine.Use(appMiddleware.RequestContext(server.logger))
+	// Sessions only decide between the primary and the replicas
+	if len(server.config.Postgresql.ReplicaDSNs) > 0 {
+		window := time.Duration(server.config.Postgresql.ReadYourWritesWindow) * time.Second
+		server.Engine.Use(appMiddleware.ReadYourWrites(window, do.MustInvoke[*cache.Redis](injector).Client()))
+	}
+
+	if server.config.Metrics.Enabled {
+		server.Engine.Use(do.MustInvoke[*metrics.Metrics](injector).Middleware())
+	}
+
+	// Origins are read on every request so they can be changed by a config reload
+	server.corsOrigins.Store(&server.config.Server.CORSAllowOrigins)
+	server.Engine.Use(middleware.CORSWithConfig(middleware.CORSConfig{
+		AllowOriginFunc: server.allowOrigin,
+	}))
 
 	server.Engine.Use(middleware.RequestLoggerWithConfig(middleware.RequestLoggerConfig{
-		LogURI:     true,
-		LogStatus:  true,
-		LogLatency: true,
-		LogMethod:  true,
+		LogURI:      true,
+		LogStatus:   true,
+		LogLatency:  true,
+		LogMethod:   true,
+		LogError:    true,
+		HandleError: true, // render the error first so the logged status is the one sent to the client
 		LogValuesFunc: func(c echo.Context, values middleware.RequestLoggerValues) error {
-			server.logger.Info().
+			level := zerolog.InfoLevel
+			switch {
+			case values.Status >= http.StatusInternalServerError:
+				level = zerolog.ErrorLevel
+			case values.Status >= http.StatusBadRequest:
+				level = zerolog.WarnLevel
+			}
+
+			event := server.logger.WithLevel(level).
+				Ctx(c.Request().Context()).
 				Str("method", values.Method).
 				Str("uri", values.URI).
 				Int("status", values.Status).
-				Dur("latency", values.Latency).
-				Msg("Request")
+				Dur("latency", values.Latency)
+			if values.Error != nil {
+				event = event.Err(values.Error)
+			}
+			event.Msg("Request")
 			return nil
 		},
 	}))
@@ -55,6 +101,52 @@ func NewHTTPServer(injector do.Injector) (*HTTPServer, error) {
 
 }
 
+// ipExtractor only trusts X-Forwarded-For when the request comes through one of the trusted
+// proxies, so clients cannot pick the IP used for login limits and logs.
+func ipExtractor(trustedProxies []string) echo.IPExtractor {
+	if len(trustedProxies) == 0 {
+		return echo.ExtractIPDirect()
+	}
+
+	options := []echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}
+	for _, cidr := range trustedProxies {
+		// Validated on startup
+		_, ipNet, _ := net.ParseCIDR(cidr)
+		options = append(options, echo.TrustIPRange(ipNet))
+	}
+	return echo.ExtractIPFromXFFHeader(options...)
+}
+
+func (s *HTTPServer) allowOrigin(origin string) (bool, error) {
+	for _, allowed := range *s.corsOrigins.Load() {
+		if allowed == "*" || allowed == origin {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// checkOrigin applies the CORS origins to WebSocket handshakes, which browsers do not guard with CORS.
+// Requests without an Origin header do not come from a browser and are allowed.
+func (s *HTTPServer) checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get(echo.HeaderOrigin)
+	if origin == "" {
+		return true
+	}
+	allowed, _ := s.allowOrigin(origin)
+	return allowed
+}
+
+// OnConfigReload applies the new CORS origins.
+func (s *HTTPServer) OnConfigReload(cfg *config.Config) {
+	origins := cfg.Server.CORSAllowOrigins
+	s.corsOrigins.Store(&origins)
+}
+
 func (s *HTTPServer) Start() error {
 	s.logger.Info().
 		Str("host", s.config.Server.Host).
@@ -63,3 +155,8 @@ func (s *HTTPServer) Start() error {
 
 	return s.Server.ListenAndServe()
 }
+
+// Shutdown stops accepting new connections and waits for in-flight requests until ctx is done.
+func (s *HTTPServer) Shutdown(ctx context.Context) error {
+	return s.Server.Shutdown(ctx)
+}
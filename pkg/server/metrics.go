@@ -0,0 +1,77 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go-api-starter/pkg/config"
+	"go-api-starter/pkg/metrics"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+	"github.com/samber/do/v2"
+)
+
+// MetricsServer exposes the Prometheus endpoint, either on the main HTTP server or on a
+// separate admin port so that metrics are not reachable through the public listener.
+type MetricsServer struct {
+	config  *config.Config
+	logger  *zerolog.Logger
+	metrics *metrics.Metrics
+	Server  *http.Server
+}
+
+func NewMetricsServer(i do.Injector) (*MetricsServer, error) {
+	server := &MetricsServer{
+		config:  do.MustInvoke[*config.Config](i),
+		logger:  do.MustInvoke[*zerolog.Logger](i),
+		metrics: do.MustInvoke[*metrics.Metrics](i),
+	}
+
+	if server.Separate() {
+		mux := http.NewServeMux()
+		mux.Handle(server.config.Metrics.Path, server.metrics.Handler())
+		server.Server = &http.Server{
+			Addr:              server.config.Metrics.Host + ":" + strconv.Itoa(server.config.Metrics.Port),
+			Handler:           mux,
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+	}
+
+	return server, nil
+}
+
+// Separate reports whether metrics are served by their own admin server.
+func (s *MetricsServer) Separate() bool {
+	return s.config.Metrics.Port != 0
+}
+
+// Register mounts the metrics endpoint on the main HTTP server unless an admin port is configured.
+func (s *MetricsServer) Register(e *echo.Echo) {
+	if s.Separate() {
+		return
+	}
+	e.GET(s.config.Metrics.Path, echo.WrapHandler(s.metrics.Handler()))
+}
+
+func (s *MetricsServer) Start() error {
+	if !s.Separate() {
+		return nil
+	}
+
+	s.logger.Info().
+		Str("host", s.config.Metrics.Host).
+		Int("port", s.config.Metrics.Port).
+		Msg("Starting metrics server")
+
+	return s.Server.ListenAndServe()
+}
+
+func (s *MetricsServer) Shutdown(ctx context.Context) error {
+	if s.Server == nil {
+		return nil
+	}
+	return s.Server.Shutdown(ctx)
+}
@@ -4,4 +4,8 @@ import "github.com/samber/do/v2"
 
 var Package = do.Package(
 	do.Lazy(NewHTTPServer),
+	do.Lazy(NewGRPCServer),
+	do.Lazy(NewWebSocketHub),
+	do.Lazy(NewHealthHandler),
+	do.Lazy(NewMetricsServer),
 )
@@ -1 +1,491 @@
-package server
\ No newline at end of file
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"go-api-starter/pkg/apperrors"
+	"go-api-starter/pkg/cache"
+	"go-api-starter/pkg/constants"
+	"go-api-starter/pkg/handler"
+	"go-api-starter/pkg/middleware"
+	"go-api-starter/pkg/utils"
+
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog"
+	"github.com/samber/do/v2"
+)
+
+const (
+	// Time allowed to write a message to the peer
+	wsWriteWait = 10 * time.Second
+
+	// Time allowed to read the next pong message from the peer
+	wsPongWait = 60 * time.Second
+
+	// Send pings to the peer with this period. Must be less than wsPongWait
+	wsPingPeriod = (wsPongWait * 9) / 10
+
+	// Maximum message size allowed from the peer
+	wsMaxMessageSize = 4096
+
+	// Number of outgoing messages buffered per connection. A client that falls this far
+	// behind is disconnected instead of slowing down the publishers.
+	wsSendBufferSize = 256
+
+	// Topics with this prefix are private to the user whose id follows it
+	wsUserTopicPrefix = "user:"
+
+	wsPath = "/ws"
+
+	// Query parameter carrying the access token of browsers, which cannot set headers on WebSocket requests
+	wsTokenQueryParam = "access_token"
+)
+
+// Close codes sent to clients, in the private range reserved for applications
+const (
+	WSCloseSessionRevoked = 4001
+	WSCloseTokenExpired   = 4002
+	WSCloseSlowConsumer   = 4003
+)
+
+// Types of the JSON frames exchanged with clients
+const (
+	WSMessageTypeSubscribe   = "subscribe"
+	WSMessageTypeUnsubscribe = "unsubscribe"
+	WSMessageTypeSubscribed  = "subscribed"
+	WSMessageTypeMessage     = "message"
+	WSMessageTypeError       = "error"
+)
+
+// WSMessage is a frame sent to or received from a WebSocket client.
+type WSMessage struct {
+	Type  string `json:"type"`
+	Topic string `json:"topic,omitempty"`
+	Data  any    `json:"data,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// TopicAuthorizer decides whether an authenticated user may subscribe to a topic.
+type TopicAuthorizer func(ctx context.Context, claims *utils.TokenClaims, topic string) bool
+
+// WebSocketHub tracks authenticated WebSocket connections and fans out messages to users and topics.
+type WebSocketHub struct {
+	logger       *zerolog.Logger
+	tokenService *utils.TokenService
+	redis        *redis.Client
+	upgrader     websocket.Upgrader
+	authorize    TopicAuthorizer
+
+	mu     sync.RWMutex
+	users  map[string]map[*wsClient]struct{}
+	topics map[string]map[*wsClient]struct{}
+
+	startOnce sync.Once
+	cancel    context.CancelFunc
+}
+
+type wsClient struct {
+	hub    *WebSocketHub
+	conn   *websocket.Conn
+	claims *utils.TokenClaims
+	send   chan []byte
+
+	// topics is only touched by the hub while holding hub.mu
+	topics map[string]struct{}
+
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+func NewWebSocketHub(i do.Injector) (*WebSocketHub, error) {
+	redisClient := do.MustInvoke[*cache.Redis](i)
+	httpServer := do.MustInvoke[*HTTPServer](i)
+	return &WebSocketHub{
+		logger:       do.MustInvoke[*zerolog.Logger](i),
+		tokenService: do.MustInvoke[*utils.TokenService](i),
+		redis:        redisClient.Client(),
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+			// Same policy as the CORS middleware of the HTTP server, including config reloads
+			CheckOrigin: httpServer.checkOrigin,
+		},
+		authorize: defaultTopicAuthorizer,
+		users:     make(map[string]map[*wsClient]struct{}),
+		topics:    make(map[string]map[*wsClient]struct{}),
+	}, nil
+}
+
+// defaultTopicAuthorizer lets users subscribe to any shared topic but only to their own private topic.
+func defaultTopicAuthorizer(_ context.Context, claims *utils.TokenClaims, topic string) bool {
+	if userID, ok := strings.CutPrefix(topic, wsUserTopicPrefix); ok {
+		return userID == claims.Subject
+	}
+	return true
+}
+
+// SetTopicAuthorizer replaces the policy deciding which topics users may subscribe to.
+func (h *WebSocketHub) SetTopicAuthorizer(authorize TopicAuthorizer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.authorize = authorize
+}
+
+// Register mounts the WebSocket endpoint and starts listening for token revocations.
+func (h *WebSocketHub) Register(e *echo.Echo) {
+	e.Pre(moveQueryToken)
+	e.GET(wsPath, h.Serve)
+
+	h.startOnce.Do(func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		h.cancel = cancel
+		go h.listenRevocations(ctx)
+	})
+}
+
+// moveQueryToken moves ?access_token= of WebSocket requests into the Authorization header.
+// It runs before routing and every other middleware, so the token never reaches request logs or traces.
+func moveQueryToken(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := c.Request()
+		if req.URL.Path != wsPath || !req.URL.Query().Has(wsTokenQueryParam) {
+			return next(c)
+		}
+
+		query := req.URL.Query()
+		token := query.Get(wsTokenQueryParam)
+		query.Del(wsTokenQueryParam)
+		req.URL.RawQuery = query.Encode()
+		req.RequestURI = req.URL.RequestURI()
+
+		if token != "" && req.Header.Get(echo.HeaderAuthorization) == "" {
+			req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+		}
+		return next(c)
+	}
+}
+
+// Serve authenticates the request with an access token and upgrades it to a WebSocket connection.
+// Browsers cannot set headers on WebSocket requests, so the token may also be passed as ?access_token=,
+// which moveQueryToken turns into an Authorization header.
+func (h *WebSocketHub) Serve(c echo.Context) error {
+	token, ok := middleware.BearerToken(c)
+	if !ok {
+		return handler.NewErrorResponse(http.StatusUnauthorized, apperrors.ErrUnauthorized, "missing bearer token")
+	}
+
+	claims, err := h.tokenService.VerifyToken(c.Request().Context(), token, constants.ScopeTokenAccess)
+	if err != nil {
+		if errors.Is(err, utils.ErrExpiredToken) {
+			return handler.NewErrorResponse(http.StatusUnauthorized, apperrors.ErrTokenExpired, "access token has expired")
+		}
+		return handler.NewErrorResponse(http.StatusUnauthorized, apperrors.ErrInvalidToken, "invalid access token")
+	}
+
+	conn, err := h.upgrader.Upgrade(c.Response(), c.Request(), nil)
+	if err != nil {
+		// The upgrader has already written an error response
+		h.logger.Warn().Ctx(c.Request().Context()).Err(err).Msg("failed to upgrade WebSocket connection")
+		return nil
+	}
+
+	client := &wsClient{
+		hub:    h,
+		conn:   conn,
+		claims: claims,
+		send:   make(chan []byte, wsSendBufferSize),
+		topics: make(map[string]struct{}),
+		done:   make(chan struct{}),
+	}
+	h.addClient(client)
+
+	go client.writePump()
+	go client.readPump()
+
+	return nil
+}
+
+// SendToUser delivers a message to every connection of the user.
+func (h *WebSocketHub) SendToUser(userID string, data any) {
+	h.mu.RLock()
+	clients := collectClients(h.users[userID])
+	h.mu.RUnlock()
+
+	h.deliver(clients, WSMessage{Type: WSMessageTypeMessage, Data: data})
+}
+
+// Publish delivers a message to every connection subscribed to the topic.
+func (h *WebSocketHub) Publish(topic string, data any) {
+	h.mu.RLock()
+	clients := collectClients(h.topics[topic])
+	h.mu.RUnlock()
+
+	h.deliver(clients, WSMessage{Type: WSMessageTypeMessage, Topic: topic, Data: data})
+}
+
+// DisconnectUser closes every connection of the user.
+func (h *WebSocketHub) DisconnectUser(userID string) {
+	h.mu.RLock()
+	clients := collectClients(h.users[userID])
+	h.mu.RUnlock()
+
+	for _, client := range clients {
+		client.close(WSCloseSessionRevoked, "session revoked")
+	}
+}
+
+// Shutdown closes all connections and stops listening for revocations.
+func (h *WebSocketHub) Shutdown(context.Context) error {
+	if h.cancel != nil {
+		h.cancel()
+	}
+
+	h.mu.RLock()
+	clients := make([]*wsClient, 0)
+	for _, userClients := range h.users {
+		clients = append(clients, collectClients(userClients)...)
+	}
+	h.mu.RUnlock()
+
+	for _, client := range clients {
+		client.close(websocket.CloseGoingAway, "server shutting down")
+	}
+	return nil
+}
+
+func (h *WebSocketHub) deliver(clients []*wsClient, message WSMessage) {
+	if len(clients) == 0 {
+		return
+	}
+
+	payload, err := json.Marshal(message)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("failed to encode WebSocket message")
+		return
+	}
+
+	for _, client := range clients {
+		client.enqueue(payload)
+	}
+}
+
+func (h *WebSocketHub) addClient(client *wsClient) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	userID := client.claims.Subject
+	if h.users[userID] == nil {
+		h.users[userID] = make(map[*wsClient]struct{})
+	}
+	h.users[userID][client] = struct{}{}
+}
+
+func (h *WebSocketHub) removeClient(client *wsClient) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	userID := client.claims.Subject
+	delete(h.users[userID], client)
+	if len(h.users[userID]) == 0 {
+		delete(h.users, userID)
+	}
+
+	for topic := range client.topics {
+		h.unsubscribeLocked(client, topic)
+	}
+}
+
+func (h *WebSocketHub) subscribe(client *wsClient, topic string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !h.authorize(context.Background(), client.claims, topic) {
+		return false
+	}
+
+	if h.topics[topic] == nil {
+		h.topics[topic] = make(map[*wsClient]struct{})
+	}
+	h.topics[topic][client] = struct{}{}
+	client.topics[topic] = struct{}{}
+	return true
+}
+
+func (h *WebSocketHub) unsubscribe(client *wsClient, topic string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.unsubscribeLocked(client, topic)
+}
+
+func (h *WebSocketHub) unsubscribeLocked(client *wsClient, topic string) {
+	delete(client.topics, topic)
+	delete(h.topics[topic], client)
+	if len(h.topics[topic]) == 0 {
+		delete(h.topics, topic)
+	}
+}
+
+// listenRevocations closes connections whose token was revoked on any instance.
+func (h *WebSocketHub) listenRevocations(ctx context.Context) {
+	pubsub := h.redis.Subscribe(ctx, constants.TokenRevokedChannel)
+	defer pubsub.Close()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-pubsub.Channel():
+			if !ok {
+				return
+			}
+
+			var event utils.TokenRevocationEvent
+			if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+				h.logger.Warn().Err(err).Msg("invalid token revocation event")
+				continue
+			}
+			h.handleRevocation(event)
+		}
+	}
+}
+
+func (h *WebSocketHub) handleRevocation(event utils.TokenRevocationEvent) {
+	h.mu.RLock()
+	clients := collectClients(h.users[event.UserID])
+	h.mu.RUnlock()
+
+	for _, client := range clients {
+		if event.TokenID != "" {
+			if client.claims.ID == event.TokenID {
+				client.close(WSCloseSessionRevoked, "session revoked")
+			}
+			continue
+		}
+
+		// Same rule as TokenService.IsTokenRevoked
+		if client.claims.IssuedAtMilli() < event.RevokedAt {
+			client.close(WSCloseSessionRevoked, "session revoked")
+		}
+	}
+}
+
+func collectClients(set map[*wsClient]struct{}) []*wsClient {
+	clients := make([]*wsClient, 0, len(set))
+	for client := range set {
+		clients = append(clients, client)
+	}
+	return clients
+}
+
+// enqueue queues a message without blocking. A client whose buffer is full is disconnected.
+func (c *wsClient) enqueue(payload []byte) {
+	select {
+	case <-c.done:
+	case c.send <- payload:
+	default:
+		c.hub.logger.Warn().Str("user_id", c.claims.Subject).Msg("disconnecting slow WebSocket client")
+		c.close(WSCloseSlowConsumer, "client is too slow")
+	}
+}
+
+// close sends a close frame and tears the connection down. It is safe to call more than once.
+func (c *wsClient) close(code int, reason string) {
+	c.closeOnce.Do(func() {
+		close(c.done)
+		c.hub.removeClient(c)
+
+		deadline := time.Now().Add(wsWriteWait)
+		_ = c.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), deadline)
+		_ = c.conn.Close()
+	})
+}
+
+func (c *wsClient) readPump() {
+	defer c.close(websocket.CloseNormalClosure, "")
+
+	c.conn.SetReadLimit(wsMaxMessageSize)
+	_ = c.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+
+	for {
+		var message WSMessage
+		if err := c.conn.ReadJSON(&message); err != nil {
+			var syntaxErr *json.SyntaxError
+			if errors.As(err, &syntaxErr) {
+				c.reply(WSMessage{Type: WSMessageTypeError, Error: "invalid message"})
+				continue
+			}
+			return
+		}
+
+		switch message.Type {
+		case WSMessageTypeSubscribe:
+			if message.Topic == "" || !c.hub.subscribe(c, message.Topic) {
+				c.reply(WSMessage{Type: WSMessageTypeError, Topic: message.Topic, Error: "subscription denied"})
+				continue
+			}
+			c.reply(WSMessage{Type: WSMessageTypeSubscribed, Topic: message.Topic})
+		case WSMessageTypeUnsubscribe:
+			c.hub.unsubscribe(c, message.Topic)
+		default:
+			c.reply(WSMessage{Type: WSMessageTypeError, Error: "unknown message type"})
+		}
+	}
+}
+
+func (c *wsClient) reply(message WSMessage) {
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return
+	}
+	c.enqueue(payload)
+}
+
+// writePump is the only writer of data frames. On every ping it also checks that the
+// token is still valid, which covers revocations whose notification was missed.
+func (c *wsClient) writePump() {
+	ticker := time.NewTicker(wsPingPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.done:
+			return
+		case payload := <-c.send:
+			_ = c.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := c.conn.WriteMessage(websocket.TextMessage, payload); err != nil {
+				c.close(websocket.CloseAbnormalClosure, "")
+				return
+			}
+		case <-ticker.C:
+			if c.claims.ExpiresAt != nil && time.Now().After(c.claims.ExpiresAt.Time) {
+				c.close(WSCloseTokenExpired, "access token has expired")
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), constants.CacheTimeout)
+			revoked, err := c.hub.tokenService.IsTokenRevoked(ctx, c.claims)
+			cancel()
+			if err == nil && revoked {
+				c.close(WSCloseSessionRevoked, "session revoked")
+				return
+			}
+
+			_ = c.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				c.close(websocket.CloseAbnormalClosure, "")
+				return
+			}
+		}
+	}
+}
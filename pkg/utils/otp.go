@@ -2,21 +2,40 @@ package utils
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
 
+	"go-api-starter/pkg/constants"
+
 	gonanoid "github.com/matoous/go-nanoid/v2"
 	"github.com/redis/go-redis/v9"
 )
 
 // Các bộ ký tự có thể sử dụng
 const (
-	AlphanumericOTP = "0123456789"
-	OTPExpiry       = 5 * time.Minute // OTP hết hạn sau 5 phút
-	MaxOTPAttempts  = 3               // Tối đa 3 lần thử
+	AlphanumericOTP  = "0123456789"
+	OTPExpiry        = 5 * time.Minute // OTP hết hạn sau 5 phút
+	MaxOTPAttempts   = 3               // Tối đa 3 lần nhập sai cho mỗi identifier trong OTPAttemptWindow
+	OTPAttemptWindow = 1 * time.Hour   // Bộ đếm lần nhập sai không bị reset khi yêu cầu OTP mới
 )
 
+var (
+	ErrOTPNotFound = errors.New("OTP not found or expired")
+	ErrOTPInvalid  = errors.New("invalid OTP code")
+)
+
+// OTPLimitError báo hiệu đã vượt quá giới hạn gửi hoặc nhập OTP
+type OTPLimitError struct {
+	RetryAfter time.Duration
+}
+
+func (e *OTPLimitError) Error() string {
+	return fmt.Sprintf("OTP limit exceeded, retry after %s", e.RetryAfter.Round(time.Second))
+}
+
 // OTPType định nghĩa loại OTP
 type OTPType string
 
@@ -24,16 +43,46 @@ const (
 	OTPTypeForgotPassword OTPType = "forgot_password"
 	OTPTypeVerification   OTPType = "verification"
 	OTPTypeLogin          OTPType = "login"
+	OTPTypeChangePassword OTPType = "change_password"
 )
 
+// otpKeyPrefixes chứa các loại OTP có Redis key riêng
+var otpKeyPrefixes = map[OTPType]string{
+	OTPTypeChangePassword: constants.RedisKeyOTPChangePassword,
+}
+
+// otpKey tạo Redis key cho OTP
+func otpKey(identifier string, otpType OTPType) string {
+	if prefix, ok := otpKeyPrefixes[otpType]; ok {
+		return prefix + identifier
+	}
+	return fmt.Sprintf("otp:%s:%s", string(otpType), identifier)
+}
+
+// otpAttemptsKey tạo Redis key đếm số lần nhập sai, tách riêng khỏi OTP
+func otpAttemptsKey(identifier string, otpType OTPType) string {
+	return otpKey(identifier, otpType) + ":attempts"
+}
+
 // OTPData chứa thông tin OTP
 type OTPData struct {
-	Code       string    `json:"code"`
-	Type       OTPType   `json:"type"`
-	Identifier string    `json:"identifier"` // phone hoặc email
-	Attempts   int       `json:"attempts"`
-	CreatedAt  time.Time `json:"created_at"`
-	ExpiresAt  time.Time `json:"expires_at"`
+	Code        string    `json:"code"`
+	ChallengeID string    `json:"challenge_id"` // ID ngẫu nhiên trả cho client, phải gửi kèm khi xác thực
+	Type        OTPType   `json:"type"`
+	Identifier  string    `json:"identifier"` // phone hoặc email
+	Attempts    int       `json:"attempts"`
+	CreatedAt   time.Time `json:"created_at"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// MarshalBinary mã hoá OTPData thành JSON để lưu vào Redis
+func (d OTPData) MarshalBinary() ([]byte, error) {
+	return json.Marshal(d)
+}
+
+// UnmarshalBinary giải mã OTPData từ JSON lưu trong Redis
+func (d *OTPData) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, d)
 }
 
 // OTPService quản lý OTP
@@ -57,7 +106,13 @@ func GenerateOTP() string {
 	return id
 }
 
-// GenerateAndStoreOTP tạo và lưu trữ OTP trong Redis
+// NewOTPChallengeID tạo challenge ID ngẫu nhiên, không tiết lộ thông tin về tài khoản
+func NewOTPChallengeID() string {
+	return gonanoid.Must()
+}
+
+// GenerateAndStoreOTP tạo và lưu trữ OTP trong Redis.
+// OTP mới thay thế OTP cũ nhưng không reset số lần nhập sai.
 func (s *OTPService) GenerateAndStoreOTP(ctx context.Context, identifier string, otpType OTPType) (*OTPData, error) {
 	// Tạo OTP code
 	code := GenerateOTP()
@@ -67,16 +122,16 @@ func (s *OTPService) GenerateAndStoreOTP(ctx context.Context, identifier string,
 
 	// Tạo OTP data
 	otpData := &OTPData{
-		Code:       code,
-		Type:       otpType,
-		Identifier: identifier,
-		Attempts:   0,
-		CreatedAt:  time.Now(),
-		ExpiresAt:  time.Now().Add(OTPExpiry),
+		Code:        code,
+		ChallengeID: NewOTPChallengeID(),
+		Type:        otpType,
+		Identifier:  identifier,
+		CreatedAt:   time.Now(),
+		ExpiresAt:   time.Now().Add(OTPExpiry),
 	}
 
 	// Tạo Redis key
-	key := fmt.Sprintf("otp:%s:%s", string(otpType), identifier)
+	key := otpKey(identifier, otpType)
 
 	// Lưu vào Redis với expiry
 	err := s.redisClient.Set(ctx, key, otpData, OTPExpiry).Err()
@@ -87,50 +142,93 @@ func (s *OTPService) GenerateAndStoreOTP(ctx context.Context, identifier string,
 	return otpData, nil
 }
 
+// verifyOTPScript so sánh OTP và đếm lần nhập sai trong một bước để các request song song
+// không thể cùng vượt qua giới hạn. Lần nhập cũng được đếm khi không có OTP, để identifier
+// không tồn tại và identifier có OTP bị giới hạn giống nhau.
+//
+// KEYS[1] OTP, KEYS[2] bộ đếm; ARGV: code, challenge ID ("" để bỏ qua), số lần tối đa, window (ms).
+// Trả về {1} khi hợp lệ, {0} khi sai, {-1} khi không có OTP, {-2, pttl} khi đã vượt giới hạn.
+var verifyOTPScript = redis.NewScript(`
+local attempts = tonumber(redis.call('GET', KEYS[2]) or '0')
+if attempts >= tonumber(ARGV[3]) then
+	return {-2, redis.call('PTTL', KEYS[2])}
+end
+
+local raw = redis.call('GET', KEYS[1])
+if raw then
+	local otp = cjson.decode(raw)
+	if otp.code == ARGV[1] and (ARGV[2] == '' or otp.challenge_id == ARGV[2]) then
+		redis.call('DEL', KEYS[1], KEYS[2])
+		return {1}
+	end
+end
+
+attempts = redis.call('INCR', KEYS[2])
+if attempts == 1 then
+	redis.call('PEXPIRE', KEYS[2], ARGV[4])
+end
+if attempts >= tonumber(ARGV[3]) then
+	redis.call('DEL', KEYS[1])
+end
+
+if raw then
+	return {0}
+end
+return {-1}
+`)
+
 // VerifyOTP xác thực OTP
 func (s *OTPService) VerifyOTP(ctx context.Context, identifier string, code string, otpType OTPType) (bool, error) {
-	// Tạo Redis key
-	key := fmt.Sprintf("otp:%s:%s", string(otpType), identifier)
+	return s.VerifyOTPChallenge(ctx, identifier, "", code, otpType)
+}
 
-	// Lấy OTP data từ Redis
-	var otpData OTPData
-	err := s.redisClient.Get(ctx, key).Scan(&otpData)
-	if err == redis.Nil {
-		return false, fmt.Errorf("OTP not found or expired")
-	}
+// VerifyOTPChallenge xác thực OTP cùng challenge ID đã trả cho client khi gửi OTP.
+// Trả về ErrOTPInvalid, ErrOTPNotFound hoặc *OTPLimitError khi không hợp lệ.
+func (s *OTPService) VerifyOTPChallenge(ctx context.Context, identifier string, challengeID string, code string, otpType OTPType) (bool, error) {
+	keys := []string{otpKey(identifier, otpType), otpAttemptsKey(identifier, otpType)}
+	result, err := verifyOTPScript.Run(ctx, s.redisClient, keys, code, challengeID, MaxOTPAttempts, OTPAttemptWindow.Milliseconds()).Int64Slice()
 	if err != nil {
-		return false, fmt.Errorf("failed to get OTP: %w", err)
+		return false, fmt.Errorf("failed to verify OTP: %w", err)
 	}
 
-	// Kiểm tra số lần thử
-	if otpData.Attempts >= MaxOTPAttempts {
-		// Xóa OTP nếu đã thử quá nhiều lần
-		s.redisClient.Del(ctx, key)
-		return false, fmt.Errorf("maximum OTP attempts exceeded")
+	switch result[0] {
+	case 1:
+		return true, nil
+	case 0:
+		return false, ErrOTPInvalid
+	case -1:
+		return false, ErrOTPNotFound
+	default:
+		return false, &OTPLimitError{RetryAfter: time.Duration(result[1]) * time.Millisecond}
 	}
+}
 
-	// Kiểm tra OTP code
-	if otpData.Code != code {
-		// Tăng số lần thử
-		otpData.Attempts++
-		s.redisClient.Set(ctx, key, otpData, time.Until(otpData.ExpiresAt))
-		return false, fmt.Errorf("invalid OTP code")
+// Limit đếm một lần gọi vào key và trả về *OTPLimitError khi vượt quá limit trong window,
+// dùng để giới hạn việc gửi và xác thực OTP theo identifier hoặc IP.
+func (s *OTPService) Limit(ctx context.Context, key string, limit int, window time.Duration) error {
+	pipe := s.redisClient.TxPipeline()
+	incr := pipe.Incr(ctx, key)
+	pipe.ExpireNX(ctx, key, window)
+	ttl := pipe.PTTL(ctx, key)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to check OTP limit: %w", err)
 	}
 
-	// OTP hợp lệ, xóa khỏi Redis
-	s.redisClient.Del(ctx, key)
-	return true, nil
+	if incr.Val() > int64(limit) {
+		return &OTPLimitError{RetryAfter: ttl.Val()}
+	}
+	return nil
 }
 
 // DeleteOTP xóa OTP khỏi Redis
 func (s *OTPService) DeleteOTP(ctx context.Context, identifier string, otpType OTPType) error {
-	key := fmt.Sprintf("otp:%s:%s", string(otpType), identifier)
+	key := otpKey(identifier, otpType)
 	return s.redisClient.Del(ctx, key).Err()
 }
 
 // GetOTPInfo lấy thông tin OTP (không bao gồm code)
 func (s *OTPService) GetOTPInfo(ctx context.Context, identifier string, otpType OTPType) (*OTPData, error) {
-	key := fmt.Sprintf("otp:%s:%s", string(otpType), identifier)
+	key := otpKey(identifier, otpType)
 
 	var otpData OTPData
 	err := s.redisClient.Get(ctx, key).Scan(&otpData)
@@ -141,8 +239,15 @@ func (s *OTPService) GetOTPInfo(ctx context.Context, identifier string, otpType
 		return nil, fmt.Errorf("failed to get OTP info: %w", err)
 	}
 
+	attempts, err := s.redisClient.Get(ctx, otpAttemptsKey(identifier, otpType)).Int()
+	if err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("failed to get OTP info: %w", err)
+	}
+	otpData.Attempts = attempts
+
 	// Không trả về code để bảo mật
 	otpData.Code = ""
+	otpData.ChallengeID = ""
 	return &otpData, nil
 }
 
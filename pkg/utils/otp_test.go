@@ -0,0 +1,156 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func newTestOTPService(t *testing.T) *OTPService {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	return NewOTPService(client)
+}
+
+func TestOTPServiceVerifyOTPChallenge(t *testing.T) {
+	ctx := context.Background()
+	const identifier = "user@example.com"
+
+	tests := []struct {
+		name string
+		// wrong is the number of wrong codes entered before the last attempt
+		wrong       int
+		noOTP       bool
+		reissue     bool
+		challengeID func(otp *OTPData) string
+		code        func(otp *OTPData) string
+		wantErr     error
+		wantLimited bool
+	}{
+		{name: "valid", challengeID: challengeOf, code: codeOf},
+		{name: "without challenge", challengeID: func(*OTPData) string { return "" }, code: codeOf},
+		{name: "wrong code", challengeID: challengeOf, code: wrongCode, wantErr: ErrOTPInvalid},
+		{name: "wrong challenge", challengeID: func(*OTPData) string { return "other" }, code: codeOf, wantErr: ErrOTPInvalid},
+		{name: "no otp", noOTP: true, challengeID: func(*OTPData) string { return "any" }, code: func(*OTPData) string { return "123456" }, wantErr: ErrOTPNotFound},
+		{name: "valid after fewer wrong codes than the limit", wrong: MaxOTPAttempts - 1, challengeID: challengeOf, code: codeOf},
+		{name: "valid code after the limit", wrong: MaxOTPAttempts, challengeID: challengeOf, code: codeOf, wantLimited: true},
+		{name: "new code does not reset the limit", wrong: MaxOTPAttempts, reissue: true, challengeID: challengeOf, code: codeOf, wantLimited: true},
+		{name: "limit applies without an otp", wrong: MaxOTPAttempts, noOTP: true, challengeID: challengeOf, code: codeOf, wantLimited: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newTestOTPService(t)
+
+			otp := &OTPData{Code: "000000", ChallengeID: "missing"}
+			if !tt.noOTP {
+				var err error
+				if otp, err = service.GenerateAndStoreOTP(ctx, identifier, OTPTypeForgotPassword); err != nil {
+					t.Fatalf("GenerateAndStoreOTP: %v", err)
+				}
+			}
+
+			for range tt.wrong {
+				if _, err := service.VerifyOTPChallenge(ctx, identifier, otp.ChallengeID, wrongCode(otp), OTPTypeForgotPassword); err == nil {
+					t.Fatal("wrong code was accepted")
+				}
+			}
+			if tt.reissue {
+				var err error
+				if otp, err = service.GenerateAndStoreOTP(ctx, identifier, OTPTypeForgotPassword); err != nil {
+					t.Fatalf("GenerateAndStoreOTP: %v", err)
+				}
+			}
+
+			valid, err := service.VerifyOTPChallenge(ctx, identifier, tt.challengeID(otp), tt.code(otp), OTPTypeForgotPassword)
+
+			var limitErr *OTPLimitError
+			if limited := errors.As(err, &limitErr); limited != tt.wantLimited {
+				t.Fatalf("err = %v, want limited %v", err, tt.wantLimited)
+			}
+			if tt.wantLimited {
+				if limitErr.RetryAfter <= 0 || limitErr.RetryAfter > OTPAttemptWindow {
+					t.Errorf("RetryAfter = %s, want within %s", limitErr.RetryAfter, OTPAttemptWindow)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if valid != (tt.wantErr == nil) {
+				t.Errorf("valid = %v, want %v", valid, tt.wantErr == nil)
+			}
+		})
+	}
+}
+
+func TestOTPServiceVerifyOTPChallengeIsSingleUse(t *testing.T) {
+	ctx := context.Background()
+	service := newTestOTPService(t)
+
+	otp, err := service.GenerateAndStoreOTP(ctx, "0912345678", OTPTypeVerification)
+	if err != nil {
+		t.Fatalf("GenerateAndStoreOTP: %v", err)
+	}
+
+	if valid, err := service.VerifyOTP(ctx, "0912345678", otp.Code, OTPTypeVerification); !valid || err != nil {
+		t.Fatalf("first use: valid = %v, err = %v", valid, err)
+	}
+	if _, err := service.VerifyOTP(ctx, "0912345678", otp.Code, OTPTypeVerification); !errors.Is(err, ErrOTPNotFound) {
+		t.Fatalf("second use: err = %v, want %v", err, ErrOTPNotFound)
+	}
+}
+
+func TestOTPServiceLimit(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		limit       int
+		calls       int
+		wantLimited bool
+	}{
+		{name: "below limit", limit: 3, calls: 2},
+		{name: "at limit", limit: 3, calls: 3},
+		{name: "above limit", limit: 3, calls: 4, wantLimited: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newTestOTPService(t)
+
+			var err error
+			for range tt.calls {
+				err = service.Limit(ctx, "otp_requests:test", tt.limit, time.Minute)
+			}
+
+			var limitErr *OTPLimitError
+			if limited := errors.As(err, &limitErr); limited != tt.wantLimited {
+				t.Fatalf("err = %v, want limited %v", err, tt.wantLimited)
+			}
+			if tt.wantLimited && (limitErr.RetryAfter <= 0 || limitErr.RetryAfter > time.Minute) {
+				t.Errorf("RetryAfter = %s, want within 1m", limitErr.RetryAfter)
+			}
+		})
+	}
+}
+
+func challengeOf(otp *OTPData) string { return otp.ChallengeID }
+
+func codeOf(otp *OTPData) string { return otp.Code }
+
+// wrongCode returns a code that differs from the stored one.
+func wrongCode(otp *OTPData) string {
+	if otp.Code == "000000" {
+		return "111111"
+	}
+	return "000000"
+}
@@ -7,6 +7,8 @@ import (
 const (
 	DefaultPageNumber = 1
 	DefaultPageSize   = 10
+	MaxPageSize       = 100    // page_size lớn hơn bị giới hạn về giá trị này
+	MaxPageNumber     = 10_000 // giới hạn OFFSET, tránh tràn số và truy vấn quá sâu
 )
 
 type QueryParams struct {
@@ -30,10 +32,18 @@ func NewQueryParams(c echo.Context) *QueryParams {
 	}
 
 	return &QueryParams{
-		PageNumber: ToNumberWithDefault(c.QueryParam("page_number"), DefaultPageNumber),
-		PageSize:   ToNumberWithDefault(c.QueryParam("page_size"), DefaultPageSize),
+		PageNumber: clamp(ToNumberWithDefault(c.QueryParam("page_number"), DefaultPageNumber), DefaultPageNumber, 1, MaxPageNumber),
+		PageSize:   clamp(ToNumberWithDefault(c.QueryParam("page_size"), DefaultPageSize), DefaultPageSize, 1, MaxPageSize),
 		Search:     c.QueryParam("search"),
 		Filters:    filters,
 		OrderBy:    c.QueryParam("order_by"),
 	}
 }
+
+// clamp giới hạn value trong [lo, hi], trả về defaultValue khi value không hợp lệ (<= 0)
+func clamp(value, defaultValue, lo, hi int) int {
+	if value <= 0 {
+		return defaultValue
+	}
+	return min(max(value, lo), hi)
+}
@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go-api-starter/pkg/config"
+
+	"github.com/rs/zerolog"
+	"github.com/samber/do/v2"
+)
+
+// Supported SMS providers
+const (
+	SMSProviderLog     = "log"
+	SMSProviderWebhook = "webhook"
+)
+
+// SMSSender delivers text messages to a phone number.
+type SMSSender interface {
+	SendSMS(ctx context.Context, to string, message string) error
+}
+
+// NewSMSSender creates the SMS sender selected by the configured provider.
+func NewSMSSender(i do.Injector) (SMSSender, error) {
+	appConfig := do.MustInvoke[*config.Config](i)
+	logger := do.MustInvoke[*zerolog.Logger](i)
+
+	switch appConfig.SMS.Provider {
+	case "", SMSProviderLog:
+		return &logSMSSender{logger: logger}, nil
+	case SMSProviderWebhook:
+		if appConfig.SMS.WebhookURL == "" {
+			return nil, fmt.Errorf("sms webhook url is required for the webhook provider")
+		}
+		return &webhookSMSSender{
+			url:    appConfig.SMS.WebhookURL,
+			client: &http.Client{Timeout: 10 * time.Second},
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported sms provider: %s", appConfig.SMS.Provider)
+	}
+}
+
+// logSMSSender writes messages to the log instead of sending them. Only meant for local development.
+type logSMSSender struct {
+	logger *zerolog.Logger
+}
+
+func (s *logSMSSender) SendSMS(ctx context.Context, to string, message string) error {
+	s.logger.Info().Ctx(ctx).Str("to", to).Str("message", message).Msg("SMS message")
+	return nil
+}
+
+// webhookSMSSender posts messages as JSON to a gateway that delivers them.
+type webhookSMSSender struct {
+	url    string
+	client *http.Client
+}
+
+func (s *webhookSMSSender) SendSMS(ctx context.Context, to string, message string) error {
+	body, err := json.Marshal(map[string]string{
+		"to":      to,
+		"message": message,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode sms payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create sms request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send sms: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("sms gateway returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
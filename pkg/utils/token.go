@@ -1 +1,250 @@
 package utils
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"go-api-starter/pkg/cache"
+	"go-api-starter/pkg/config"
+	"go-api-starter/pkg/constants"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/samber/do/v2"
+)
+
+// Supported token signing algorithms
+const (
+	TokenAlgorithmHS256 = "HS256"
+	TokenAlgorithmRS256 = "RS256"
+	TokenAlgorithmEdDSA = "EdDSA"
+)
+
+var (
+	ErrInvalidToken      = errors.New("invalid token")
+	ErrExpiredToken      = errors.New("token has expired")
+	ErrInvalidTokenScope = errors.New("invalid token scope")
+)
+
+// TokenClaims holds the claims carried by every token issued by the API.
+// IssuedAtMs repeats iat in milliseconds, since iat only has second precision and a token
+// issued right after a revoke-all in the same second must stay valid.
+// Binding ties a token to a value besides its subject, e.g. a hash of the email address an
+// email verification token was sent to; checking it is up to the caller.
+type TokenClaims struct {
+	Scope      string `json:"scope"`
+	IssuedAtMs int64  `json:"iat_ms,omitempty"`
+	Binding    string `json:"bnd,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// IssuedAtMilli returns when the token was issued in Unix milliseconds, falling back to iat
+// for tokens issued without iat_ms.
+func (c *TokenClaims) IssuedAtMilli() int64 {
+	if c.IssuedAtMs != 0 {
+		return c.IssuedAtMs
+	}
+	if c.IssuedAt != nil {
+		return c.IssuedAt.UnixMilli()
+	}
+	return 0
+}
+
+// TokenService signs and verifies scoped tokens and keeps track of revoked ones in Redis.
+type TokenService struct {
+	redis      *redis.Client
+	issuer     string
+	method     jwt.SigningMethod
+	signKey    any
+	verifyKey  any
+	scopeTTLs  map[string]time.Duration
+	defaultTTL time.Duration
+}
+
+// NewTokenService creates a token service from the JWT configuration.
+func NewTokenService(i do.Injector) (*TokenService, error) {
+	appConfig := do.MustInvoke[*config.Config](i)
+	redisClient := do.MustInvoke[*cache.Redis](i)
+	return NewTokenServiceWithConfig(appConfig.JWT, redisClient.Client())
+}
+
+// NewTokenServiceWithConfig creates a token service from an explicit JWT configuration.
+func NewTokenServiceWithConfig(cfg config.JWTConfig, redisClient *redis.Client) (*TokenService, error) {
+	service := &TokenService{
+		redis:  redisClient,
+		issuer: cfg.Issuer,
+		scopeTTLs: map[string]time.Duration{
+			constants.ScopeTokenAccess:            time.Duration(cfg.AccessTokenTTL) * time.Second,
+			constants.ScopeTokenRefresh:           time.Duration(cfg.RefreshTokenTTL) * time.Second,
+			constants.ScopeTokenResetPassword:     time.Duration(cfg.ResetPasswordTokenTTL) * time.Second,
+			constants.ScopeTokenEmailVerification: time.Duration(cfg.EmailVerificationTokenTTL) * time.Second,
+		},
+		defaultTTL: time.Duration(cfg.AccessTokenTTL) * time.Second,
+	}
+
+	algorithm := strings.TrimSpace(cfg.Algorithm)
+	if algorithm == "" {
+		algorithm = TokenAlgorithmHS256
+	}
+
+	switch algorithm {
+	case TokenAlgorithmHS256:
+		if cfg.Secret == "" {
+			return nil, fmt.Errorf("jwt secret is required for %s", algorithm)
+		}
+		service.method = jwt.SigningMethodHS256
+		service.signKey = []byte(cfg.Secret)
+		service.verifyKey = []byte(cfg.Secret)
+	case TokenAlgorithmRS256:
+		service.method = jwt.SigningMethodRS256
+		if err := service.loadKeys(cfg, parseRSAPrivateKey, parseRSAPublicKey); err != nil {
+			return nil, err
+		}
+	case TokenAlgorithmEdDSA:
+		service.method = jwt.SigningMethodEdDSA
+		if err := service.loadKeys(cfg, jwt.ParseEdPrivateKeyFromPEM, jwt.ParseEdPublicKeyFromPEM); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported jwt algorithm: %s", algorithm)
+	}
+
+	return service, nil
+}
+
+func parseRSAPrivateKey(key []byte) (crypto.PrivateKey, error) {
+	return jwt.ParseRSAPrivateKeyFromPEM(key)
+}
+
+func parseRSAPublicKey(key []byte) (crypto.PublicKey, error) {
+	return jwt.ParseRSAPublicKeyFromPEM(key)
+}
+
+// loadKeys reads the PEM encoded key pair used by asymmetric algorithms.
+// The private key is optional so that verify-only services can run with the public key alone.
+func (s *TokenService) loadKeys(
+	cfg config.JWTConfig,
+	parsePrivate func([]byte) (crypto.PrivateKey, error),
+	parsePublic func([]byte) (crypto.PublicKey, error),
+) error {
+	if cfg.PublicKeyPath == "" {
+		return fmt.Errorf("jwt public key path is required for %s", s.method.Alg())
+	}
+
+	publicPEM, err := os.ReadFile(cfg.PublicKeyPath)
+	if err != nil {
+		return fmt.Errorf("failed to read jwt public key: %w", err)
+	}
+	publicKey, err := parsePublic(publicPEM)
+	if err != nil {
+		return fmt.Errorf("failed to parse jwt public key: %w", err)
+	}
+	s.verifyKey = publicKey
+
+	if cfg.PrivateKeyPath == "" {
+		return nil
+	}
+
+	privatePEM, err := os.ReadFile(cfg.PrivateKeyPath)
+	if err != nil {
+		return fmt.Errorf("failed to read jwt private key: %w", err)
+	}
+	privateKey, err := parsePrivate(privatePEM)
+	if err != nil {
+		return fmt.Errorf("failed to parse jwt private key: %w", err)
+	}
+	s.signKey = privateKey
+
+	return nil
+}
+
+// TTL returns the lifetime of tokens issued for the given scope.
+func (s *TokenService) TTL(scope string) time.Duration {
+	if ttl, ok := s.scopeTTLs[scope]; ok && ttl > 0 {
+		return ttl
+	}
+	return s.defaultTTL
+}
+
+// GenerateToken signs a token for the subject with the given scope.
+// Every token gets a unique jti so it can be revoked individually.
+func (s *TokenService) GenerateToken(subject string, scope string) (string, *TokenClaims, error) {
+	return s.GenerateBoundToken(subject, scope, "")
+}
+
+// GenerateBoundToken is GenerateToken with the binding claim set, see TokenClaims.
+func (s *TokenService) GenerateBoundToken(subject string, scope string, binding string) (string, *TokenClaims, error) {
+	if s.signKey == nil {
+		return "", nil, fmt.Errorf("token service has no signing key configured")
+	}
+
+	now := time.Now()
+	claims := &TokenClaims{
+		Scope:      scope,
+		IssuedAtMs: now.UnixMilli(),
+		Binding:    binding,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),
+			Issuer:    s.issuer,
+			Subject:   subject,
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(s.TTL(scope))),
+		},
+	}
+
+	token, err := jwt.NewWithClaims(s.method, claims).SignedString(s.signKey)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to sign token: %w", err)
+	}
+
+	return token, claims, nil
+}
+
+// VerifyToken checks the signature, expiry, issuer, scope and revocation status of a token
+// and returns its claims. A token issued for another scope, e.g. a reset password token
+// presented as an access token, is rejected with ErrInvalidTokenScope.
+func (s *TokenService) VerifyToken(ctx context.Context, tokenString string, expectedScope string) (*TokenClaims, error) {
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{s.method.Alg()}),
+		jwt.WithExpirationRequired(),
+	}
+	if s.issuer != "" {
+		options = append(options, jwt.WithIssuer(s.issuer))
+	}
+
+	claims := &TokenClaims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (any, error) {
+		return s.verifyKey, nil
+	}, options...)
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, ErrExpiredToken
+		}
+		return nil, ErrInvalidToken
+	}
+
+	if claims.ID == "" || claims.Subject == "" {
+		return nil, ErrInvalidToken
+	}
+
+	if claims.Scope != expectedScope {
+		return nil, ErrInvalidTokenScope
+	}
+
+	revoked, err := s.IsTokenRevoked(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		return nil, ErrRevokedToken
+	}
+
+	return claims, nil
+}
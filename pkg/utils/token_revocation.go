@@ -0,0 +1,117 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go-api-starter/pkg/constants"
+)
+
+var ErrRevokedToken = errors.New("token has been revoked")
+
+// legacyRevocationThreshold separates revoke-all timestamps in Unix seconds from those in
+// milliseconds; 1e12 milliseconds is in 2001, 1e12 seconds is far in the future.
+const legacyRevocationThreshold = 1_000_000_000_000
+
+// TokenRevocationEvent is published on constants.TokenRevokedChannel whenever tokens are revoked,
+// so that long-lived connections authenticated with them can be closed.
+// TokenID is empty when every token of the user issued before RevokedAt was revoked.
+// RevokedAt is in Unix milliseconds.
+type TokenRevocationEvent struct {
+	UserID    string `json:"user_id"`
+	TokenID   string `json:"token_id,omitempty"`
+	RevokedAt int64  `json:"revoked_at"`
+}
+
+// RevokeToken blacklists a single token by its jti until the token would have expired anyway.
+func (s *TokenService) RevokeToken(ctx context.Context, claims *TokenClaims) error {
+	if claims.ExpiresAt == nil {
+		return fmt.Errorf("token has no expiry")
+	}
+
+	ttl := time.Until(claims.ExpiresAt.Time)
+	if ttl <= 0 {
+		return nil
+	}
+
+	if err := s.redis.Set(ctx, constants.TokenBlacklistKey+claims.ID, claims.Subject, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to revoke token: %w", err)
+	}
+
+	s.publishRevocation(ctx, TokenRevocationEvent{
+		UserID:    claims.Subject,
+		TokenID:   claims.ID,
+		RevokedAt: time.Now().UnixMilli(),
+	})
+	return nil
+}
+
+// RevokeAllUserTokens revokes every token issued to the user up to now.
+// Only the revocation timestamp, in milliseconds, is stored; it is kept for as long as the
+// longest-lived token can exist.
+func (s *TokenService) RevokeAllUserTokens(ctx context.Context, userID string) error {
+	now := time.Now().UnixMilli()
+	revokedAt := strconv.FormatInt(now, 10)
+	if err := s.redis.Set(ctx, constants.TokenUserRevokedKey+userID, revokedAt, s.maxTTL()).Err(); err != nil {
+		return fmt.Errorf("failed to revoke user tokens: %w", err)
+	}
+
+	s.publishRevocation(ctx, TokenRevocationEvent{UserID: userID, RevokedAt: now})
+	return nil
+}
+
+// publishRevocation notifies subscribers of a revocation. The revocation itself is already stored,
+// so a failed notification only delays closing connections until their token is checked again.
+func (s *TokenService) publishRevocation(ctx context.Context, event TokenRevocationEvent) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	_ = s.redis.Publish(ctx, constants.TokenRevokedChannel, payload).Err()
+}
+
+// IsTokenRevoked reports whether the token was revoked individually or through a revoke-all of its subject.
+func (s *TokenService) IsTokenRevoked(ctx context.Context, claims *TokenClaims) (bool, error) {
+	values, err := s.redis.MGet(ctx,
+		constants.TokenBlacklistKey+claims.ID,
+		constants.TokenUserRevokedKey+claims.Subject,
+	).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to check token revocation: %w", err)
+	}
+
+	if values[0] != nil {
+		return true, nil
+	}
+
+	if revokedAt, ok := values[1].(string); ok {
+		timestamp, err := strconv.ParseInt(revokedAt, 10, 64)
+		if err != nil {
+			return false, fmt.Errorf("invalid user revocation timestamp: %w", err)
+		}
+		// Revocations stored before millisecond precision hold Unix seconds
+		if timestamp < legacyRevocationThreshold {
+			timestamp *= 1000
+		}
+		if claims.IssuedAtMilli() < timestamp {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// maxTTL returns the lifetime of the longest-lived token scope.
+func (s *TokenService) maxTTL() time.Duration {
+	maxTTL := s.defaultTTL
+	for _, ttl := range s.scopeTTLs {
+		if ttl > maxTTL {
+			maxTTL = ttl
+		}
+	}
+	return maxTTL
+}
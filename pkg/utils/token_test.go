@@ -0,0 +1,309 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"go-api-starter/pkg/config"
+	"go-api-starter/pkg/constants"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/redis/go-redis/v9"
+)
+
+const testTokenSecret = "test-secret"
+
+func newTestTokenService(t *testing.T) (*TokenService, *miniredis.Miniredis) {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	service, err := NewTokenServiceWithConfig(config.JWTConfig{
+		Algorithm:             TokenAlgorithmHS256,
+		Secret:                testTokenSecret,
+		Issuer:                "go-api-starter",
+		AccessTokenTTL:        900,
+		RefreshTokenTTL:       3600,
+		ResetPasswordTokenTTL: 600,
+	}, client)
+	if err != nil {
+		t.Fatalf("NewTokenServiceWithConfig: %v", err)
+	}
+	return service, server
+}
+
+// signTestToken signs claims with the test secret, bypassing GenerateToken.
+func signTestToken(t *testing.T, method jwt.SigningMethod, key any, claims *TokenClaims) string {
+	t.Helper()
+
+	token, err := jwt.NewWithClaims(method, claims).SignedString(key)
+	if err != nil {
+		t.Fatalf("SignedString: %v", err)
+	}
+	return token
+}
+
+func testClaims(scope string, issuedAt time.Time, ttl time.Duration) *TokenClaims {
+	return &TokenClaims{
+		Scope:      scope,
+		IssuedAtMs: issuedAt.UnixMilli(),
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        "token-id",
+			Issuer:    "go-api-starter",
+			Subject:   "user-id",
+			IssuedAt:  jwt.NewNumericDate(issuedAt),
+			ExpiresAt: jwt.NewNumericDate(issuedAt.Add(ttl)),
+		},
+	}
+}
+
+func TestNewTokenServiceWithConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.JWTConfig
+		wantErr bool
+	}{
+		{name: "hs256 with secret", cfg: config.JWTConfig{Algorithm: TokenAlgorithmHS256, Secret: "secret"}},
+		{name: "algorithm defaults to hs256", cfg: config.JWTConfig{Secret: "secret"}},
+		{name: "hs256 without secret", cfg: config.JWTConfig{Algorithm: TokenAlgorithmHS256}, wantErr: true},
+		{name: "rs256 without public key", cfg: config.JWTConfig{Algorithm: TokenAlgorithmRS256}, wantErr: true},
+		{name: "rs256 with missing key file", cfg: config.JWTConfig{Algorithm: TokenAlgorithmRS256, PublicKeyPath: "does-not-exist.pem"}, wantErr: true},
+		{name: "unsupported algorithm", cfg: config.JWTConfig{Algorithm: "HS512", Secret: "secret"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewTokenServiceWithConfig(tt.cfg, nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTokenServiceGenerateToken(t *testing.T) {
+	service, _ := newTestTokenService(t)
+
+	tests := []struct {
+		scope   string
+		wantTTL time.Duration
+	}{
+		{scope: constants.ScopeTokenAccess, wantTTL: 900 * time.Second},
+		{scope: constants.ScopeTokenRefresh, wantTTL: time.Hour},
+		{scope: constants.ScopeTokenResetPassword, wantTTL: 10 * time.Minute},
+		// Not configured, falls back to the access token TTL
+		{scope: constants.ScopeTokenEmailVerification, wantTTL: 900 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.scope, func(t *testing.T) {
+			_, claims, err := service.GenerateToken("user-id", tt.scope)
+			if err != nil {
+				t.Fatalf("GenerateToken: %v", err)
+			}
+			if claims.ID == "" {
+				t.Error("token has no jti")
+			}
+			if claims.Scope != tt.scope {
+				t.Errorf("scope = %q, want %q", claims.Scope, tt.scope)
+			}
+			if ttl := claims.ExpiresAt.Sub(claims.IssuedAt.Time); ttl != tt.wantTTL {
+				t.Errorf("ttl = %s, want %s", ttl, tt.wantTTL)
+			}
+			if claims.IssuedAtMilli() != claims.IssuedAtMs {
+				t.Errorf("IssuedAtMilli = %d, want iat_ms %d", claims.IssuedAtMilli(), claims.IssuedAtMs)
+			}
+		})
+	}
+}
+
+func TestTokenServiceGenerateBoundToken(t *testing.T) {
+	service, _ := newTestTokenService(t)
+	ctx := context.Background()
+
+	token, _, err := service.GenerateBoundToken("user-id", constants.ScopeTokenEmailVerification, "binding")
+	if err != nil {
+		t.Fatalf("GenerateBoundToken: %v", err)
+	}
+	claims, err := service.VerifyToken(ctx, token, constants.ScopeTokenEmailVerification)
+	if err != nil {
+		t.Fatalf("VerifyToken: %v", err)
+	}
+	if claims.Binding != "binding" {
+		t.Fatalf("binding = %q, want %q", claims.Binding, "binding")
+	}
+
+	token, _, err = service.GenerateToken("user-id", constants.ScopeTokenEmailVerification)
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+	claims, err = service.VerifyToken(ctx, token, constants.ScopeTokenEmailVerification)
+	if err != nil {
+		t.Fatalf("VerifyToken: %v", err)
+	}
+	if claims.Binding != "" {
+		t.Fatalf("binding = %q, want none", claims.Binding)
+	}
+}
+
+func TestTokenServiceVerifyToken(t *testing.T) {
+	service, _ := newTestTokenService(t)
+	now := time.Now()
+
+	valid, _, err := service.GenerateToken("user-id", constants.ScopeTokenAccess)
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+
+	withoutID := testClaims(constants.ScopeTokenAccess, now, time.Minute)
+	withoutID.ID = ""
+	otherIssuer := testClaims(constants.ScopeTokenAccess, now, time.Minute)
+	otherIssuer.Issuer = "someone-else"
+	withoutExpiry := testClaims(constants.ScopeTokenAccess, now, time.Minute)
+	withoutExpiry.ExpiresAt = nil
+
+	tests := []struct {
+		name    string
+		token   string
+		scope   string
+		wantErr error
+	}{
+		{name: "valid", token: valid, scope: constants.ScopeTokenAccess},
+		{name: "other scope", token: valid, scope: constants.ScopeTokenResetPassword, wantErr: ErrInvalidTokenScope},
+		{name: "malformed", token: "not-a-token", scope: constants.ScopeTokenAccess, wantErr: ErrInvalidToken},
+		{
+			name:    "expired",
+			token:   signTestToken(t, jwt.SigningMethodHS256, []byte(testTokenSecret), testClaims(constants.ScopeTokenAccess, now.Add(-time.Hour), time.Minute)),
+			scope:   constants.ScopeTokenAccess,
+			wantErr: ErrExpiredToken,
+		},
+		{
+			name:    "other secret",
+			token:   signTestToken(t, jwt.SigningMethodHS256, []byte("other-secret"), testClaims(constants.ScopeTokenAccess, now, time.Minute)),
+			scope:   constants.ScopeTokenAccess,
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "other algorithm",
+			token:   signTestToken(t, jwt.SigningMethodHS512, []byte(testTokenSecret), testClaims(constants.ScopeTokenAccess, now, time.Minute)),
+			scope:   constants.ScopeTokenAccess,
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "other issuer",
+			token:   signTestToken(t, jwt.SigningMethodHS256, []byte(testTokenSecret), otherIssuer),
+			scope:   constants.ScopeTokenAccess,
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "without jti",
+			token:   signTestToken(t, jwt.SigningMethodHS256, []byte(testTokenSecret), withoutID),
+			scope:   constants.ScopeTokenAccess,
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "without expiry",
+			token:   signTestToken(t, jwt.SigningMethodHS256, []byte(testTokenSecret), withoutExpiry),
+			scope:   constants.ScopeTokenAccess,
+			wantErr: ErrInvalidToken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := service.VerifyToken(context.Background(), tt.token, tt.scope)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && claims.Subject != "user-id" {
+				t.Errorf("subject = %q, want user-id", claims.Subject)
+			}
+		})
+	}
+}
+
+func TestTokenServiceRevocation(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	tests := []struct {
+		name string
+		// revoke runs before the token issued at issuedAt is verified
+		revoke      func(t *testing.T, service *TokenService, server *miniredis.Miniredis, claims *TokenClaims)
+		issuedAt    time.Time
+		wantRevoked bool
+	}{
+		{
+			name:     "not revoked",
+			revoke:   func(*testing.T, *TokenService, *miniredis.Miniredis, *TokenClaims) {},
+			issuedAt: now,
+		},
+		{
+			name: "revoked individually",
+			revoke: func(t *testing.T, service *TokenService, _ *miniredis.Miniredis, claims *TokenClaims) {
+				if err := service.RevokeToken(ctx, claims); err != nil {
+					t.Fatalf("RevokeToken: %v", err)
+				}
+			},
+			issuedAt:    now,
+			wantRevoked: true,
+		},
+		{
+			name: "issued before revoke-all",
+			revoke: func(t *testing.T, service *TokenService, _ *miniredis.Miniredis, claims *TokenClaims) {
+				if err := service.RevokeAllUserTokens(ctx, claims.Subject); err != nil {
+					t.Fatalf("RevokeAllUserTokens: %v", err)
+				}
+			},
+			issuedAt:    now.Add(-time.Millisecond),
+			wantRevoked: true,
+		},
+		{
+			name: "issued after revoke-all in the same second",
+			revoke: func(_ *testing.T, _ *TokenService, server *miniredis.Miniredis, claims *TokenClaims) {
+				revokedAt := claims.IssuedAtMilli() - 1
+				server.Set(constants.TokenUserRevokedKey+claims.Subject, strconv.FormatInt(revokedAt, 10))
+			},
+			issuedAt: now,
+		},
+		{
+			name: "issued before a revoke-all stored in seconds",
+			revoke: func(_ *testing.T, _ *TokenService, server *miniredis.Miniredis, claims *TokenClaims) {
+				server.Set(constants.TokenUserRevokedKey+claims.Subject, strconv.FormatInt(now.Unix(), 10))
+			},
+			issuedAt:    now.Add(-time.Minute),
+			wantRevoked: true,
+		},
+		{
+			name: "issued after a revoke-all stored in seconds",
+			revoke: func(_ *testing.T, _ *TokenService, server *miniredis.Miniredis, claims *TokenClaims) {
+				server.Set(constants.TokenUserRevokedKey+claims.Subject, strconv.FormatInt(now.Add(-time.Minute).Unix(), 10))
+			},
+			issuedAt: now,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, server := newTestTokenService(t)
+			claims := testClaims(constants.ScopeTokenAccess, tt.issuedAt, time.Hour)
+			token := signTestToken(t, jwt.SigningMethodHS256, []byte(testTokenSecret), claims)
+
+			tt.revoke(t, service, server, claims)
+
+			_, err := service.VerifyToken(ctx, token, constants.ScopeTokenAccess)
+			if revoked := errors.Is(err, ErrRevokedToken); revoked != tt.wantRevoked {
+				t.Fatalf("err = %v, want revoked %v", err, tt.wantRevoked)
+			}
+			if !tt.wantRevoked && err != nil {
+				t.Fatalf("VerifyToken: %v", err)
+			}
+		})
+	}
+}